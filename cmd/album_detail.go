@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	"github.com/bytegrunt/go-spotify-me/internal/logging"
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Relative column widths of the album tracklist
+var albumColRatios = []float64{0.06, 0.54, 0.12, 0.28}
+
+// albumDetail holds an album and its full tracklist.
+type albumDetail struct {
+	album  Album
+	tracks []Song
+	saved  map[string]bool // Tracks in the user's Liked Songs
+	table  table.Model
+}
+
+type switchToAlbumMsg struct {
+	detail albumDetail
+}
+
+// fetchAlbumCmd fetches an album with its tracklist and opens it.
+func fetchAlbumCmd(id string) tea.Cmd {
+	return func() tea.Msg {
+		detail, err := fetchAlbumDetail(id)
+		if err != nil {
+			return errMsg{err}
+		}
+		return switchToAlbumMsg{detail}
+	}
+}
+
+func fetchAlbumDetail(id string) (albumDetail, error) {
+	token, _ := auth.GetValidAccessToken()
+	response, err := MakeAPIRequest(token, "https://api.spotify.com/v1/albums/"+id)
+	if err != nil {
+		return albumDetail{}, err
+	}
+
+	detail := albumDetail{album: parseAlbum(response)}
+
+	// The album carries the first page of its tracks; follow the rest
+	if tracks, ok := response["tracks"].(map[string]interface{}); ok {
+		detail.tracks = parseSongs(tracks)
+		if next, _ := tracks["next"].(string); next != "" {
+			rest, err := collectSongs(next)
+			if err != nil {
+				return albumDetail{}, err
+			}
+			detail.tracks = append(detail.tracks, rest...)
+		}
+	}
+
+	// Album tracks are simplified and do not name their album
+	ids := make([]string, len(detail.tracks))
+	for i := range detail.tracks {
+		detail.tracks[i].Album = detail.album.Name
+		detail.tracks[i].AlbumID = detail.album.ID
+		detail.tracks[i].ReleaseDate = detail.album.ReleaseDate
+		ids[i] = detail.tracks[i].ID
+	}
+
+	// Saved state only adds highlighting, so a failure is not fatal
+	detail.saved, err = fetchSavedTracks(ids)
+	if err != nil {
+		logging.DebugLog("Failed to check saved tracks: %v", err)
+	}
+
+	return detail, nil
+}
+
+// inTopTracks reports whether the song is among the user's top songs in any
+// time range fetched so far.
+func (m appModel) inTopTracks(id string) bool {
+	for _, songs := range m.rangeSongs {
+		for _, song := range songs {
+			if song.ID == id {
+				return true
+			}
+		}
+	}
+	for _, song := range m.songs.Songs {
+		if song.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// albumRows builds the tracklist rows, marking top and saved tracks.
+func (m appModel) albumRows(d albumDetail) []table.Row {
+	var rows []table.Row
+	for _, song := range d.tracks {
+		var marks []string
+		if m.inTopTracks(song.ID) {
+			marks = append(marks, "★ Top")
+		}
+		if d.saved[song.ID] {
+			marks = append(marks, "♥ Saved")
+		}
+		rows = append(rows, table.Row{
+			fmt.Sprintf("%d", song.TrackNumber),
+			song.Name,
+			formatDuration(song.DurationMs),
+			strings.Join(marks, " "),
+		})
+	}
+	return rows
+}
+
+// openAlbum opens the album of the selection in the current view.
+func (m appModel) openAlbum() tea.Cmd {
+	switch m.currentView {
+	case viewTrackDetail:
+		if id := m.top().track.AlbumID; id != "" {
+			return fetchAlbumCmd(id)
+		}
+	case viewSavedAlbums:
+		if albums, i := m.lists[viewSavedAlbums].response.Albums, m.selectedIndex(viewSavedAlbums); i >= 0 && i < len(albums) {
+			return fetchAlbumCmd(albums[i].ID)
+		}
+	case viewArtistDetail:
+		d := m.top().artist
+		if i := d.tables[artistAlbums].Cursor(); d.section == artistAlbums && i >= 0 && i < len(d.albums) {
+			return fetchAlbumCmd(d.albums[i].ID)
+		}
+	}
+	return nil
+}
+
+// pushAlbum shows a freshly fetched album.
+func (m *appModel) pushAlbum(detail albumDetail) {
+	detail.table = table.New(
+		table.WithColumns([]table.Column{
+			{Title: "#", Width: 4},
+			{Title: "Title", Width: 40},
+			{Title: "Duration", Width: 8},
+			{Title: "In Library", Width: 16},
+		}),
+		table.WithRows(m.albumRows(detail)),
+		table.WithFocused(true),
+	)
+	m.push(navEntry{view: viewAlbum, title: detail.album.Name, album: &detail})
+}
+
+func (m appModel) renderAlbum() string {
+	d := m.top().album
+	a := d.album
+
+	totalMs := 0
+	for _, song := range d.tracks {
+		totalMs += song.DurationMs
+	}
+
+	info := fmt.Sprintf("  %s · %s · Released %s · %d tracks · %s", a.Artist, a.AlbumType, a.ReleaseDate, len(d.tracks), formatDuration(totalMs))
+	if a.Label != "" {
+		info += "\n  Label: " + a.Label
+	}
+
+	width := max(m.windowSize.Width-10, 20)
+	highlight := func(i int) bool {
+		return i < len(d.tracks) && (m.inTopTracks(d.tracks[i].ID) || d.saved[d.tracks[i].ID])
+	}
+	body := m.renderTableStyled(d.table, calculateColumnWidths(width, albumColRatios), tableChrome+3, highlight, nil)
+
+	return theme.TitleStyle.Render(a.Name) + "\n" + info + "\n" + body + "\n" +
+		theme.HelpStyle.Render("[↑/↓] Navigate  [enter] Track Details  [q] Back  [H] Home")
+}
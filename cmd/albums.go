@@ -0,0 +1,62 @@
+package cmd
+
+// Album represents an album, single or compilation
+type Album struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Artist      string `json:"artist"`
+	AlbumType   string `json:"album_type"`
+	ReleaseDate string `json:"release_date"`
+	Label       string `json:"label,omitempty"`
+	TotalTracks int    `json:"total_tracks"`
+	URL         string `json:"url"`
+}
+
+// parseAlbumItems parses a list of album objects. Saved albums wrap the album
+// object, which is unwrapped.
+func parseAlbumItems(items []interface{}) []Album {
+	var albums []Album
+	for _, item := range items {
+		album, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if wrapped, ok := album["album"].(map[string]interface{}); ok {
+			album = wrapped
+		}
+		albums = append(albums, parseAlbum(album))
+	}
+	return albums
+}
+
+func parseAlbum(album map[string]interface{}) Album {
+	id, _ := album["id"].(string)
+	name, _ := album["name"].(string)
+	albumType, _ := album["album_type"].(string)
+	releaseDate, _ := album["release_date"].(string)
+	label, _ := album["label"].(string)
+	totalTracks, _ := album["total_tracks"].(float64)
+
+	artistName := ""
+	if artistList, ok := album["artists"].([]interface{}); ok && len(artistList) > 0 {
+		if firstArtist, ok := artistList[0].(map[string]interface{}); ok {
+			artistName, _ = firstArtist["name"].(string)
+		}
+	}
+
+	url := ""
+	if externalURLs, ok := album["external_urls"].(map[string]interface{}); ok {
+		url, _ = externalURLs["spotify"].(string)
+	}
+
+	return Album{
+		ID:          id,
+		Name:        name,
+		Artist:      artistName,
+		AlbumType:   albumType,
+		ReleaseDate: releaseDate,
+		Label:       label,
+		TotalTracks: int(totalTracks),
+		URL:         url,
+	}
+}
@@ -1,20 +1,77 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"strings"
 )
 
+// maxPages guards loops that follow next links against paging forever.
+const maxPages = 200
+
+// errPageLimit is returned, along with what was collected, by collectors that
+// stopped following next links at maxPages.
+var errPageLimit = fmt.Errorf("stopped after %d pages, the rest was left out", maxPages)
+
 // MakeAPIRequest makes a GET request to the Spotify API and returns the response or an error
 func MakeAPIRequest(token string, url string) (map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", url, nil)
+	body, err := doAPIRequest(token, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseJSONObject(body)
+}
+
+// parseJSONObject parses a response body holding a JSON object.
+func parseJSONObject(body []byte) (map[string]interface{}, error) {
+	var response map[string]interface{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+	return response, nil
+}
+
+// makeAPIListRequest makes a GET request to an endpoint that responds with a
+// JSON array, such as the /contains endpoints.
+func makeAPIListRequest(token string, url string) ([]interface{}, error) {
+	body, err := doAPIRequest(token, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []interface{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	return response, nil
+}
+
+// doAPIRequest sends a request to the Spotify API, encoding payload as the
+// JSON body unless it is nil, and returns the response body. Any 2xx status
+// counts as success, since write endpoints answer with 201 or 204.
+func doAPIRequest(token, method, url string, payload interface{}) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
@@ -27,15 +84,18 @@ func MakeAPIRequest(token string, url string) (map[string]interface{}, error) {
 		}
 	}()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status code %d: %s", resp.StatusCode, string(body))
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API response: %w", err)
 	}
 
-	var response map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	// A token granted before a scope was requested lacks it for good
+	if resp.StatusCode == http.StatusForbidden && strings.Contains(string(body), "Insufficient client scope") {
+		return nil, fmt.Errorf("the login lacks a permission this needs, run %s --clear-config and log in again", appName)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("API request failed with status code %d: %s", resp.StatusCode, string(body))
 	}
 
-	return response, nil
+	return body, nil
 }
@@ -15,20 +15,66 @@ const (
 	viewArtists
 	viewSongs
 	viewEnterClientID
+	viewCompare
+	viewArtistDetail
+	viewTrackDetail
+	viewAlbum
+	viewSearch
+	viewRecent
+	viewLiked
+	viewSavedAlbums
+	viewFollowed
+	viewPlaylists
+	viewPlaylistTracks
+	viewNowPlaying
+	viewDevices
+	viewQueue
+	viewPlaylistPicker
+	viewRecommendations
+	viewGenres
+	viewGenreArtists
 )
 
 type appModel struct {
-	currentView     viewType
+	currentView     viewType // Always the view of the top of stack
 	clientID        string
+	timeRange       string          // Time range for top artists and songs
+	loadAll         bool            // Fetch every top item instead of one page at a time
 	me              Me              // User information
 	textInput       textinput.Model // Text input for Client ID
 	artists         APIResponse
 	songs           APIResponse
 	artistTable     table.Model // Table for artists
+	artistList      tableRows   // Every artist row and the filter on them
 	artistColWidths []int
 	songTable       table.Model // Table for songs
+	songList        tableRows   // Every song row and the filter on them
 	songColWidths   []int
+	lists           map[viewType]*pagedList // State of the paged list views
+	libraryCounts   map[viewType]int        // Number of items in each library view
+	playlist        Playlist                // Playlist shown in viewPlaylistTracks
+	playback        playbackState           // Last fetched player state
+	playbackErr     error                   // Why the last playback command failed
+	toast           string                  // Confirmation shown in the status line
+	toastSeq        int                     // Incremented per toast; only the latest one expires it
+	prompt          playlistPrompt          // Asks for the name of a playlist to save songs to
+	pickSong        Song                    // Track being added to the playlist picked in viewPlaylistPicker
+	lastEdit        *appliedEdit            // Last playlist edit, for undo
+	likedSongs      map[string]bool         // Whether each top song shown is in Liked Songs
+	followedArtists map[string]bool         // Whether each top artist shown is followed
+	recs            recommendationsState    // Seeds and sliders of the recommendations view
+	genres          genresState             // Genre breakdown of the top artists
+	rangeArtists    map[string][]Artist     // Every top artist per time range, for rank changes
+	rangeSongs      map[string][]Song       // Every top song per time range, for rank changes
+	pendingRanges   map[string]bool         // Background range fetches in flight
+	rangeErrs       map[string]error        // Why the last background fetch of each range failed, keyed as pendingRanges
+	compareView     viewType                // viewArtists or viewSongs, the items being compared
+	compareRanges   int                     // Index into compareRangeSets
+	compareOffset   int                     // Scroll offset of the comparison panes
+	stack           []navEntry              // Views navigated through, the current view last
+	search          searchState             // State of the search view, kept between visits
 	windowSize      tea.WindowSizeMsg
+	startView       viewType // View to open once the Client ID is entered
 	err             error
 }
 
@@ -37,10 +83,13 @@ func (m appModel) Init() tea.Cmd {
 		tea.EnterAltScreen,
 		tea.ClearScreen,
 		tea.WindowSize(),
+		m.loadView(m.currentView),
+		m.startBackground(),
 	)
 }
 
-func InitialAppModel(clientID string) appModel {
+func InitialAppModel(clientID string, start StartView) appModel {
+	startView, _ := lookupStartView(start.View)
 	if clientID == "" {
 		ti := textinput.New()
 		ti.Placeholder = "Enter your Spotify Client ID"
@@ -49,8 +98,23 @@ func InitialAppModel(clientID string) appModel {
 		ti.Width = 50
 
 		return appModel{
-			currentView: viewEnterClientID,
-			textInput:   ti,
+			currentView:     viewEnterClientID,
+			stack:           []navEntry{{view: viewEnterClientID, title: viewTitles[viewEnterClientID]}},
+			timeRange:       start.Range,
+			startView:       startView,
+			textInput:       ti,
+			artistList:      newTableRows(),
+			songList:        newTableRows(),
+			lists:           newPagedLists(),
+			likedSongs:      make(map[string]bool),
+			followedArtists: make(map[string]bool),
+			rangeArtists:    make(map[string][]Artist),
+			rangeSongs:      make(map[string][]Song),
+			pendingRanges:   make(map[string]bool),
+			rangeErrs:       make(map[string]error),
+			search:          newSearchState(),
+			recs:            newRecommendationsState(),
+			genres:          newGenresState(),
 		}
 	}
 
@@ -71,21 +135,61 @@ func InitialAppModel(clientID string) appModel {
 		}
 	}
 
-	// Initialize artist table
-	artistColWidths := calculateColumnWidths(100, []float64{0.4, 0.4, 0.2})
-	artistTable := table.New(
+	m := appModel{
+		currentView:     viewMenu,
+		stack:           []navEntry{{view: viewMenu, title: viewTitles[viewMenu]}},
+		clientID:        clientID,
+		timeRange:       start.Range,
+		me:              me,
+		artistTable:     newArtistTable(),
+		artistList:      newTableRows(),
+		artistColWidths: calculateColumnWidths(100, artistColRatios),
+		songTable:       newSongTable(),
+		songList:        newTableRows(),
+		songColWidths:   calculateColumnWidths(100, songColRatios),
+		lists:           newPagedLists(),
+		likedSongs:      make(map[string]bool),
+		followedArtists: make(map[string]bool),
+		rangeArtists:    make(map[string][]Artist),
+		rangeSongs:      make(map[string][]Song),
+		pendingRanges:   make(map[string]bool),
+		rangeErrs:       make(map[string]error),
+		search:          newSearchState(),
+		recs:            newRecommendationsState(),
+		genres:          newGenresState(),
+	}
+	if startView != viewMenu {
+		m.pushView(startView)
+	}
+	return m
+}
+
+// Relative column widths of the artist and song tables
+var (
+	artistColRatios = []float64{0.05, 0.06, 0.04, 0.32, 0.36, 0.17}
+	songColRatios   = []float64{0.05, 0.06, 0.04, 0.29, 0.18, 0.18, 0.2}
+)
+
+func newArtistTable() table.Model {
+	return table.New(
 		table.WithColumns([]table.Column{
+			{Title: "#", Width: 4},
+			{Title: "Δ", Width: 4},
+			{Title: "✓", Width: 2},
 			{Title: "Name", Width: 40},
 			{Title: "Genres", Width: 50},
 			{Title: "Popularity", Width: 10},
 		}),
 		table.WithFocused(false),
 	)
+}
 
-	// Initialize song table
-	songColWidths := calculateColumnWidths(100, []float64{0.4, 0.3, 0.2, 0.1})
-	songTable := table.New(
+func newSongTable() table.Model {
+	return table.New(
 		table.WithColumns([]table.Column{
+			{Title: "#", Width: 4},
+			{Title: "Δ", Width: 4},
+			{Title: "♥", Width: 2},
 			{Title: "Name", Width: 40},
 			{Title: "Artist", Width: 20},
 			{Title: "Album", Width: 30},
@@ -93,14 +197,52 @@ func InitialAppModel(clientID string) appModel {
 		}),
 		table.WithFocused(false),
 	)
+}
 
-	return appModel{
-		currentView:     viewMenu,
-		clientID:        clientID,
-		me:              me,
-		artistTable:     artistTable,
-		artistColWidths: artistColWidths,
-		songTable:       songTable,
-		songColWidths:   songColWidths,
+// focusView focuses the table backing the given view.
+func (m *appModel) focusView(view viewType) {
+	switch view {
+	case viewArtists:
+		m.artistTable.Focus()
+	case viewSongs:
+		m.songTable.Focus()
+	default:
+		if l, ok := m.lists[view]; ok {
+			l.table.Focus()
+		}
+	}
+}
+
+// loadView returns the command that fetches the data for the given view in the
+// current time range, or nil if the view has nothing to load.
+func (m appModel) loadView(view viewType) tea.Cmd {
+	switch view {
+	case viewArtists:
+		if m.loadAll {
+			return fetchAllArtistsCmd(m.timeRange, m.currentView)
+		}
+		return fetchArtistsCmd(topArtistsURL(m.timeRange), m.currentView)
+	case viewSongs:
+		if m.loadAll {
+			return fetchAllSongsCmd(m.timeRange, m.currentView)
+		}
+		return fetchSongsCmd(topSongsURL(m.timeRange), m.currentView)
+	case viewGenres:
+		return m.fetchRangeArtistsCmds()
+	default:
+		if source, ok := listSources[view]; ok {
+			return fetchPageCmd(view, source.url, 0, m.currentView)
+		}
+	}
+	return nil
+}
+
+// startBackground returns the commands that keep running beside the views:
+// fetching the library counts shown on the menu and polling the currently
+// playing track. It returns nil before logging in.
+func (m appModel) startBackground() tea.Cmd {
+	if m.currentView == viewEnterClientID || m.err != nil {
+		return nil
 	}
+	return tea.Batch(fetchLibraryCountsCmd(), startStatusLine())
 }
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	"github.com/bytegrunt/go-spotify-me/internal/logging"
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Sections of the artist detail view
+const (
+	artistTopTracks = iota
+	artistAlbums
+	artistRelated
+)
+
+var artistSectionTitles = []string{"Top Tracks", "Albums & Singles", "Related Artists"}
+
+// Relative column widths of the artist detail tables
+var (
+	artistTopTrackColRatios = []float64{0.5, 0.35, 0.15}
+	artistAlbumColRatios    = []float64{0.5, 0.15, 0.2, 0.15}
+	artistRelatedColRatios  = []float64{0.35, 0.5, 0.15}
+)
+
+// artistDetail holds everything shown on an artist's detail screen.
+type artistDetail struct {
+	artist    Artist
+	topTracks []Song
+	albums    []Album
+	related   []Artist
+	section   int            // Focused section
+	tables    [3]table.Model // One table per section
+}
+
+type switchToArtistDetailMsg struct {
+	detail artistDetail
+}
+
+// fetchArtistDetailCmd fetches an artist's detail and opens it.
+func fetchArtistDetailCmd(id string) tea.Cmd {
+	return func() tea.Msg {
+		detail, err := fetchArtistDetail(id)
+		if err != nil {
+			return errMsg{err}
+		}
+		return switchToArtistDetailMsg{detail}
+	}
+}
+
+func fetchArtistDetail(id string) (artistDetail, error) {
+	token, _ := auth.GetValidAccessToken()
+	base := "https://api.spotify.com/v1/artists/" + id
+
+	response, err := MakeAPIRequest(token, base)
+	if err != nil {
+		return artistDetail{}, err
+	}
+	detail := artistDetail{artist: parseArtist(response)}
+
+	response, err = MakeAPIRequest(token, base+"/top-tracks?market=from_token")
+	if err != nil {
+		return artistDetail{}, err
+	}
+	if tracks, ok := response["tracks"].([]interface{}); ok {
+		detail.topTracks = parseSongItems(tracks, 0)
+	}
+
+	response, err = MakeAPIRequest(token, base+"/albums?include_groups=album,single&limit=50")
+	if err != nil {
+		return artistDetail{}, err
+	}
+	if items, ok := response["items"].([]interface{}); ok {
+		detail.albums = parseAlbumItems(items)
+	}
+
+	// Spotify no longer serves related artists to every app, so their absence
+	// should not hide the rest of the screen.
+	response, err = MakeAPIRequest(token, base+"/related-artists")
+	if err != nil {
+		logging.DebugLog("Failed to fetch related artists: %v", err)
+	} else if artists, ok := response["artists"].([]interface{}); ok {
+		detail.related = parseArtistItems(artists, 0)
+	}
+
+	detail.tables = detail.newTables()
+	return detail, nil
+}
+
+func (d artistDetail) newTables() [3]table.Model {
+	var topTrackRows, albumRows, relatedRows []table.Row
+	for _, song := range d.topTracks {
+		topTrackRows = append(topTrackRows, table.Row{song.Name, song.Album, fmt.Sprintf("%d", song.Popularity)})
+	}
+	for _, album := range d.albums {
+		albumRows = append(albumRows, table.Row{album.Name, album.AlbumType, album.ReleaseDate, fmt.Sprintf("%d", album.TotalTracks)})
+	}
+	for _, artist := range d.related {
+		relatedRows = append(relatedRows, table.Row{artist.Name, artist.Genres, fmt.Sprintf("%d", artist.Popularity)})
+	}
+
+	newTable := func(columns []string, rows []table.Row) table.Model {
+		cols := make([]table.Column, len(columns))
+		for i, title := range columns {
+			cols[i] = table.Column{Title: title, Width: 20}
+		}
+		return table.New(table.WithColumns(cols), table.WithRows(rows), table.WithFocused(true))
+	}
+
+	return [3]table.Model{
+		newTable([]string{"Name", "Album", "Popularity"}, topTrackRows),
+		newTable([]string{"Name", "Type", "Released", "Tracks"}, albumRows),
+		newTable([]string{"Name", "Genres", "Popularity"}, relatedRows),
+	}
+}
+
+// openArtistDetail opens the artist at the cursor of the given view.
+func (m appModel) openArtistDetail() tea.Cmd {
+	switch m.currentView {
+	case viewArtists:
+		if i := m.artistList.index(m.artistTable.Cursor()); i >= 0 && i < len(m.artists.Artists) {
+			return fetchArtistDetailCmd(m.artists.Artists[i].ID)
+		}
+	case viewFollowed:
+		if artists, i := m.lists[viewFollowed].response.Artists, m.selectedIndex(viewFollowed); i >= 0 && i < len(artists) {
+			return fetchArtistDetailCmd(artists[i].ID)
+		}
+	case viewArtistDetail:
+		d := m.top().artist
+		if i := d.tables[artistRelated].Cursor(); d.section == artistRelated && i >= 0 && i < len(d.related) {
+			return fetchArtistDetailCmd(d.related[i].ID)
+		}
+	case viewGenreArtists:
+		if artists, i := m.genres.genre.artists, m.genres.artistTable.Cursor(); i >= 0 && i < len(artists) {
+			return fetchArtistDetailCmd(artists[i].ID)
+		}
+	}
+	return nil
+}
+
+// pushArtistDetail shows a newly fetched artist.
+func (m *appModel) pushArtistDetail(detail artistDetail) {
+	m.push(navEntry{view: viewArtistDetail, title: detail.artist.Name, artist: &detail})
+}
+
+func (m appModel) renderArtistDetail() string {
+	d := m.top().artist
+	title := theme.TitleStyle.Render(d.artist.Name)
+
+	info := fmt.Sprintf("  Followers: %s  ·  Popularity: %d", formatCount(d.artist.Followers), d.artist.Popularity)
+	if d.artist.Genres != "" {
+		info += "\n  Genres: " + d.artist.Genres
+	}
+
+	tabs := make([]string, len(artistSectionTitles))
+	for i, sectionTitle := range artistSectionTitles {
+		if i == d.section {
+			tabs[i] = theme.HighlightStyle.Render("[" + sectionTitle + "]")
+		} else {
+			tabs[i] = theme.MutedStyle.Render(" " + sectionTitle + " ")
+		}
+	}
+
+	ratios := [][]float64{artistTopTrackColRatios, artistAlbumColRatios, artistRelatedColRatios}[d.section]
+	width := max(m.windowSize.Width-10, 20)
+	body := m.renderTableWithin(d.tables[d.section], calculateColumnWidths(width, ratios), tableChrome+5)
+
+	help := theme.HelpStyle.Render("[↑/↓] Navigate  [tab] Next Section  [enter] Open  [q] Back  [H] Home")
+	return title + "\n" + info + "\n\n  " + strings.Join(tabs, " ") + "\n" + body + "\n" + help
+}
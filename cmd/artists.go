@@ -4,13 +4,71 @@ import (
 	"strings"
 
 	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // Artist represents an artist's details
 type Artist struct {
-	Name       string
-	Genres     string
-	Popularity int
+	ID         string `json:"id"`
+	Rank       int    `json:"rank"` // Position in the list the artist was fetched from, starting at 1
+	Name       string `json:"name"`
+	Genres     string `json:"genres"`
+	Popularity int    `json:"popularity"`
+	Followers  int    `json:"followers,omitempty"`
+}
+
+// topArtistsURL returns the URL of the first page of the user's top artists.
+func topArtistsURL(timeRange string) string {
+	return "https://api.spotify.com/v1/me/top/artists?time_range=" + timeRange
+}
+
+// fetchArtistsCmd fetches a page of artists and switches to the Artists view
+// from the given view.
+func fetchArtistsCmd(url string, from viewType) tea.Cmd {
+	return func() tea.Msg {
+		response, err := fetchArtistsPage(url)
+		if err != nil {
+			return errMsg{err}
+		}
+		return switchToArtistsMsg{response, from}
+	}
+}
+
+// fetchAllArtistsCmd fetches every top artist for the time range and switches
+// to the Artists view from the given view.
+func fetchAllArtistsCmd(timeRange string, from viewType) tea.Cmd {
+	return func() tea.Msg {
+		artists, err := fetchAllArtists(timeRange)
+		if err != nil {
+			return errMsg{err}
+		}
+		return switchToArtistsMsg{APIResponse{Artists: artists}, from}
+	}
+}
+
+// fetchAllArtists follows the pages of the user's top artists until Spotify
+// stops returning a next page, dropping any artist that appears twice.
+func fetchAllArtists(timeRange string) ([]Artist, error) {
+	var artists []Artist
+	seen := make(map[string]bool)
+
+	url := topArtistsURL(timeRange) + "&limit=" + topItemsPageSize
+	for page := 0; url != "" && page < maxPages; page++ {
+		response, err := fetchArtistsPage(url)
+		if err != nil {
+			return nil, err
+		}
+		for _, artist := range response.Artists {
+			if seen[artist.ID] {
+				continue
+			}
+			seen[artist.ID] = true
+			artists = append(artists, artist)
+		}
+		url = response.Next
+	}
+
+	return artists, nil
 }
 
 func fetchArtistsPage(url string) (APIResponse, error) {
@@ -37,32 +95,54 @@ func parseArtists(response map[string]interface{}) []Artist {
 		return nil
 	}
 
+	offset, _ := response["offset"].(float64)
+	return parseArtistItems(items, int(offset))
+}
+
+// parseArtistItems parses a list of artist objects, ranking them from offset+1.
+func parseArtistItems(items []interface{}, offset int) []Artist {
 	var artists []Artist
-	for _, item := range items {
+	for i, item := range items {
 		artist, ok := item.(map[string]interface{})
 		if !ok {
 			continue
 		}
 
-		name := artist["name"].(string)
+		parsed := parseArtist(artist)
+		parsed.Rank = offset + i + 1
+		artists = append(artists, parsed)
+	}
 
-		genresInterface, ok := artist["genres"].([]interface{})
-		if !ok {
-			genresInterface = []interface{}{}
-		}
-		var genres []string
-		for _, genre := range genresInterface {
-			genres = append(genres, genre.(string))
+	return artists
+}
+
+func parseArtist(artist map[string]interface{}) Artist {
+	id, _ := artist["id"].(string)
+	name, _ := artist["name"].(string)
+
+	genresInterface, ok := artist["genres"].([]interface{})
+	if !ok {
+		genresInterface = []interface{}{}
+	}
+	var genres []string
+	for _, genre := range genresInterface {
+		if genre, ok := genre.(string); ok {
+			genres = append(genres, genre)
 		}
+	}
 
-		popularity := int(artist["popularity"].(float64))
+	popularity, _ := artist["popularity"].(float64)
 
-		artists = append(artists, Artist{
-			Name:       name,
-			Genres:     strings.Join(genres, ", "),
-			Popularity: popularity,
-		})
+	followers := 0.0
+	if followersObject, ok := artist["followers"].(map[string]interface{}); ok {
+		followers, _ = followersObject["total"].(float64)
 	}
 
-	return artists
+	return Artist{
+		ID:         id,
+		Name:       name,
+		Genres:     strings.Join(genres, ", "),
+		Popularity: int(popularity),
+		Followers:  int(followers),
+	}
 }
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const appName = "go-spotify-me"
+
+// command describes a subcommand of the CLI. The same definition drives both
+// argument parsing and shell completion, so a flag only has to be declared once.
+type command struct {
+	name  string
+	usage string
+	// flags registers the command's flags on fs and returns the function that
+	// runs the command once they have been parsed.
+	flags func(fs *flag.FlagSet) func(args []string) error
+	// complete returns completion candidates for the value of the named flag,
+	// or for positional arguments when flag is empty. It may be nil.
+	complete func(flag string) []string
+}
+
+// values returns the completion candidates for the given flag of c.
+func (c command) values(flag string) []string {
+	if c.complete == nil {
+		return nil
+	}
+	return c.complete(flag)
+}
+
+// commands returns every subcommand known to the CLI.
+func commands() []command {
+	return []command{
+		tuiCommand(),
+		exportCommand(),
+		playlistCommand(),
+		completionCommand(),
+	}
+}
+
+// globalFlags are the flags accepted in place of a subcommand.
+var globalFlags = []string{"--clear-config"}
+
+func lookupCommand(name string) (command, bool) {
+	for _, c := range commands() {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// Execute runs the subcommand named by args[0]. It reports false when args do
+// not name a subcommand, so the caller can fall back to its default behaviour.
+func Execute(args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	if args[0] == completeCommandName {
+		for _, candidate := range completions(args[1:]) {
+			fmt.Println(candidate)
+		}
+		return true, nil
+	}
+
+	c, ok := lookupCommand(args[0])
+	if !ok {
+		return false, nil
+	}
+
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s\n", appName, c.usage)
+		fs.PrintDefaults()
+	}
+	run := c.flags(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return true, nil
+		}
+		return true, err
+	}
+
+	return true, run(fs.Args())
+}
+
+// newFlagSet returns the flag set of c with its flags registered, for
+// inspection without running the command.
+func (c command) newFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	c.flags(fs)
+	return fs
+}
+
+// filterPrefix returns the candidates that start with prefix.
+func filterPrefix(candidates []string, prefix string) []string {
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// minPaneWidth is the narrowest a comparison pane gets before the panes are
+// stacked instead of placed side by side.
+const minPaneWidth = 30
+
+// compareItem is an entry in a comparison pane.
+type compareItem struct {
+	ID    string
+	Rank  int
+	Label string
+}
+
+// compareRangeSets are the sets of time ranges the comparison view cycles
+// through.
+var compareRangeSets = [][]string{
+	{shortTerm, mediumTerm, longTerm},
+	{shortTerm, longTerm},
+	{shortTerm, mediumTerm},
+	{mediumTerm, longTerm},
+}
+
+// compareLists returns the items of each compared time range, or false if a
+// range has not been fetched yet.
+func (m appModel) compareLists() ([][]compareItem, bool) {
+	ranges := compareRangeSets[m.compareRanges]
+	lists := make([][]compareItem, len(ranges))
+	for i, timeRange := range ranges {
+		if m.compareView == viewArtists {
+			artists, ok := m.rangeArtists[timeRange]
+			if !ok {
+				return nil, false
+			}
+			for _, artist := range artists {
+				lists[i] = append(lists[i], compareItem{artist.ID, artist.Rank, artist.Name})
+			}
+		} else {
+			songs, ok := m.rangeSongs[timeRange]
+			if !ok {
+				return nil, false
+			}
+			for _, song := range songs {
+				lists[i] = append(lists[i], compareItem{song.ID, song.Rank, song.Name + " – " + song.Artist})
+			}
+		}
+	}
+	return lists, true
+}
+
+// compareErr returns why a compared time range could not be fetched, or nil.
+func (m appModel) compareErr() error {
+	kind := "songs:"
+	if m.compareView == viewArtists {
+		kind = "artists:"
+	}
+	for _, timeRange := range compareRangeSets[m.compareRanges] {
+		if err := m.rangeErrs[kind+timeRange]; err != nil {
+			return fmt.Errorf("could not fetch %s: %w", timeRangeLabel(timeRange), err)
+		}
+	}
+	return nil
+}
+
+// retryCompare fetches the compared time ranges that failed again.
+func (m *appModel) retryCompare() tea.Cmd {
+	if m.compareView == viewArtists {
+		return m.fetchRangeArtistsCmds()
+	}
+	return m.fetchRangeSongsCmds()
+}
+
+// compareLength returns the length of the longest compared list.
+func (m appModel) compareLength() int {
+	lists, _ := m.compareLists()
+	longest := 0
+	for _, list := range lists {
+		longest = max(longest, len(list))
+	}
+	return longest
+}
+
+// commonItems returns the IDs of the items present in every list.
+func commonItems(lists [][]compareItem) map[string]bool {
+	counts := make(map[string]int)
+	for _, list := range lists {
+		for _, item := range list {
+			counts[item.ID]++
+		}
+	}
+
+	common := make(map[string]bool)
+	for id, count := range counts {
+		if count == len(lists) {
+			common[id] = true
+		}
+	}
+	return common
+}
+
+func (m appModel) renderCompare() string {
+	kind := "Top Songs"
+	if m.compareView == viewArtists {
+		kind = "Top Artists"
+	}
+	title := theme.TitleStyle.Render("Compare " + kind)
+	help := theme.HelpStyle.Render("[↑/↓] Scroll  [t] Toggle Ranges  [q] Back  [H] Home")
+
+	lists, ok := m.compareLists()
+	if err := m.compareErr(); !ok && err != nil {
+		return title + "\n\n  " + err.Error() + "\n  Press t to try again.\n" + help
+	}
+	if !ok {
+		return title + "\n\n  Loading time ranges...\n" + help
+	}
+	common := commonItems(lists)
+	ranges := compareRangeSets[m.compareRanges]
+
+	width := m.windowSize.Width
+	if width < minPaneWidth {
+		width = minPaneWidth
+	}
+
+	// Side by side when every pane fits, stacked otherwise
+	stacked := width/len(lists) < minPaneWidth+paneChrome
+	ratios := make([]float64, len(lists))
+	for i := range ratios {
+		ratios[i] = 1 / float64(len(lists))
+	}
+	paneWidths := calculateColumnWidths(width, ratios)
+
+	height := m.windowSize.Height - 8
+	if stacked {
+		paneWidths = make([]int, len(lists))
+		for i := range paneWidths {
+			paneWidths[i] = width
+		}
+		height = height/len(lists) - paneChrome
+	}
+	if m.windowSize.Height == 0 || height < 1 {
+		height = 10
+	}
+
+	panes := make([]string, len(lists))
+	for i, list := range lists {
+		panes[i] = m.renderComparePane(timeRangeLabel(ranges[i]), list, common, paneWidths[i]-paneChrome, height)
+	}
+
+	var body string
+	if stacked {
+		body = lipgloss.JoinVertical(lipgloss.Left, panes...)
+	} else {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, panes...)
+	}
+
+	summary := fmt.Sprintf("  %d in common across %s", len(common), strings.Join(rangeLabels(ranges), ", "))
+	return title + "\n" + body + "\n" + theme.HighlightStyle.Render(summary) + "\n" + help
+}
+
+// paneChrome is the horizontal space taken by a pane's border and padding.
+const paneChrome = 4
+
+func (m appModel) renderComparePane(title string, items []compareItem, common map[string]bool, width, height int) string {
+	lines := []string{theme.HeaderStyle.Render(theme.TruncateOrPad(title, width-2))}
+
+	start := m.compareOffset
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + height
+	if end > len(items) {
+		end = len(items)
+	}
+
+	for _, item := range items[start:end] {
+		line := theme.TruncateOrPad(fmt.Sprintf("%3d. %s", item.Rank, item.Label), width)
+		if common[item.ID] {
+			line = theme.HighlightStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	return theme.PaneStyle.Render(strings.Join(lines, "\n"))
+}
+
+func rangeLabels(ranges []string) []string {
+	labels := make([]string, len(ranges))
+	for i, timeRange := range ranges {
+		labels[i] = timeRangeLabel(timeRange)
+	}
+	return labels
+}
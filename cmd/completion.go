@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// completeCommandName is the hidden subcommand the completion scripts call
+// back into. It prints one candidate per line for the last word of its
+// arguments, so dynamic values never have to be baked into the scripts.
+const completeCommandName = "__complete"
+
+var completionScripts = map[string]string{
+	"bash": `# bash completion for go-spotify-me
+_go_spotify_me() {
+    local line="${COMP_LINE:0:COMP_POINT}"
+    local -a words
+    read -r -a words <<< "$line"
+    [[ "$line" == *" " ]] && words+=("")
+
+    local IFS=$'\n'
+    COMPREPLY=($(go-spotify-me __complete "${words[@]:1}" 2>/dev/null))
+
+    # bash splits --flag=value on "=", so only the value part is replaced.
+    local cur="${words[${#words[@]}-1]}"
+    if [[ "$cur" == --*=* && "$COMP_WORDBREAKS" == *"="* ]]; then
+        COMPREPLY=("${COMPREPLY[@]#*=}")
+    fi
+}
+complete -o default -F _go_spotify_me go-spotify-me
+`,
+	"zsh": `#compdef go-spotify-me
+# zsh completion for go-spotify-me
+_go_spotify_me() {
+    local -a candidates
+    candidates=(${(f)"$(go-spotify-me __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+    compadd -Q -- "${candidates[@]}"
+}
+
+# Autoloaded from $fpath, this file is the completion function itself and has
+# to complete the first TAB too; sourced, it only registers the function.
+if [[ "${zsh_eval_context[-1]}" == loadautofunc ]]; then
+    _go_spotify_me "$@"
+else
+    compdef _go_spotify_me go-spotify-me
+fi
+`,
+	"fish": `# fish completion for go-spotify-me
+function __go_spotify_me_complete
+    set -l tokens (commandline -opc) (commandline -ct)
+    go-spotify-me __complete $tokens[2..-1] 2>/dev/null
+end
+complete -c go-spotify-me -f -a '(__go_spotify_me_complete)'
+`,
+}
+
+func completionShells() []string {
+	shells := make([]string, 0, len(completionScripts))
+	for shell := range completionScripts {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+	return shells
+}
+
+func completionCommand() command {
+	return command{
+		name:  "completion",
+		usage: "completion bash|zsh|fish",
+		flags: func(fs *flag.FlagSet) func(args []string) error {
+			return func(args []string) error {
+				if len(args) != 1 {
+					return fmt.Errorf("usage: %s completion %s", appName, strings.Join(completionShells(), "|"))
+				}
+				script, ok := completionScripts[args[0]]
+				if !ok {
+					return fmt.Errorf("unsupported shell %q, expected one of: %s", args[0], strings.Join(completionShells(), ", "))
+				}
+				fmt.Print(script)
+				return nil
+			}
+		},
+		complete: func(flag string) []string {
+			if flag == "" {
+				return completionShells()
+			}
+			return nil
+		},
+	}
+}
+
+// completions returns the candidates for the last of words, given the words
+// before it. words does not include the program name.
+func completions(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur := words[len(words)-1]
+	prev := words[:len(words)-1]
+
+	// First word: a subcommand or a global flag
+	if len(prev) == 0 {
+		candidates := append([]string{}, globalFlags...)
+		for _, c := range commands() {
+			candidates = append(candidates, c.name)
+		}
+		return filterPrefix(candidates, cur)
+	}
+
+	c, ok := lookupCommand(prev[0])
+	if !ok {
+		return nil
+	}
+	fs := c.newFlagSet()
+
+	if strings.HasPrefix(cur, "-") {
+		// --flag=value completes the value in place
+		if i := strings.Index(cur, "="); i >= 0 {
+			name := strings.TrimLeft(cur[:i], "-")
+			var candidates []string
+			for _, value := range filterPrefix(c.values(name), cur[i+1:]) {
+				candidates = append(candidates, cur[:i+1]+value)
+			}
+			return candidates
+		}
+
+		var names []string
+		fs.VisitAll(func(f *flag.Flag) {
+			names = append(names, "--"+f.Name)
+		})
+		return filterPrefix(names, cur)
+	}
+
+	// A value for the preceding flag, unless it is a boolean switch
+	if last := prev[len(prev)-1]; len(prev) > 1 && strings.HasPrefix(last, "-") && !strings.Contains(last, "=") {
+		if f := fs.Lookup(strings.TrimLeft(last, "-")); f != nil && !isBoolFlag(f) {
+			return filterPrefix(c.values(f.Name), cur)
+		}
+	}
+
+	return filterPrefix(c.values(""), cur)
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,8 +9,8 @@ import (
 	"github.com/zalando/go-keyring"
 )
 
-// ClearConfig removes the client_id and refresh_token from the keyring
-// and clears the .go-spotify-me-cli file in the user's home directory.
+// ClearConfig removes the client_id, refresh_token and default start view from
+// the keyring and clears the .go-spotify-me-cli file in the user's home directory.
 func ClearConfig() error {
 	// Remove client_id from the keyring
 	if err := keyring.Delete("go-spotify-me-cli", "client_id"); err != nil {
@@ -21,6 +22,13 @@ func ClearConfig() error {
 		fmt.Printf("Failed to delete refresh_token from keyring: %v\n", err)
 	}
 
+	// Remove the default start view from the keyring, if one was saved
+	for _, key := range []string{"default_view", "default_range"} {
+		if err := keyring.Delete("go-spotify-me-cli", key); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			fmt.Printf("Failed to delete %s from keyring: %v\n", key, err)
+		}
+	}
+
 	// Clear the .go-spotify-me-cli file
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
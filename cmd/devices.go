@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Device is a Spotify Connect device the user can play on
+type Device struct {
+	ID         string
+	Name       string
+	Type       string
+	Volume     int
+	Active     bool
+	Restricted bool // Cannot be controlled through the Web API
+}
+
+const devicesURL = "https://api.spotify.com/v1/me/player/devices"
+
+var devicesSource = listSource{
+	url: devicesURL,
+	columns: []table.Column{
+		{Title: "Name", Width: 40},
+		{Title: "Type", Width: 16},
+		{Title: "Volume", Width: 8},
+		{Title: "Active", Width: 8},
+	},
+	ratios: []float64{0.45, 0.25, 0.15, 0.15},
+	fetch:  fetchDevices,
+	rows: func(response APIResponse, offset int) []table.Row {
+		rows := []table.Row{}
+		for _, device := range response.Devices {
+			volume := fmt.Sprintf("%d%%", device.Volume)
+			if device.Restricted {
+				volume = "-"
+			}
+			rows = append(rows, table.Row{device.Name, device.Type, volume, yesNo(device.Active)})
+		}
+		return rows
+	},
+	footer: theme.HelpStyle.Render("[↑/↓] Navigate  [enter] Play Here  [q] Back  [H] Home"),
+}
+
+// fetchDevices fetches the user's devices. They come as a single page.
+func fetchDevices(url string) (APIResponse, error) {
+	token, _ := auth.GetValidAccessToken()
+	response, err := MakeAPIRequest(token, url)
+	if err != nil {
+		return APIResponse{}, err
+	}
+
+	var devices []Device
+	items, _ := response["devices"].([]interface{})
+	for _, item := range items {
+		device, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := device["id"].(string)
+		name, _ := device["name"].(string)
+		deviceType, _ := device["type"].(string)
+		volume, _ := device["volume_percent"].(float64)
+		active, _ := device["is_active"].(bool)
+		restricted, _ := device["is_restricted"].(bool)
+		devices = append(devices, Device{
+			ID:         id,
+			Name:       name,
+			Type:       deviceType,
+			Volume:     int(volume),
+			Active:     active,
+			Restricted: restricted,
+		})
+	}
+
+	return APIResponse{Devices: devices}, nil
+}
+
+// transferMsg carries the devices and player state after moving playback.
+type transferMsg struct {
+	devices APIResponse
+	state   playbackState
+	err     error
+}
+
+// transferPlaybackCmd moves playback to a device, keeping it playing or
+// paused as it was.
+func transferPlaybackCmd(id string) tea.Cmd {
+	return func() tea.Msg {
+		token, _ := auth.GetValidAccessToken()
+		payload := map[string]interface{}{"device_ids": []string{id}}
+		if _, err := doAPIRequest(token, "PUT", playerURL, payload); err != nil {
+			return transferMsg{err: err}
+		}
+
+		time.Sleep(playerSettleDelay)
+		devices, err := fetchDevices(devicesURL)
+		if err != nil {
+			return transferMsg{err: err}
+		}
+		state, err := fetchPlaybackState()
+		return transferMsg{devices, state, err}
+	}
+}
+
+// transferToSelected moves playback to the device under the cursor.
+func (m *appModel) transferToSelected() tea.Cmd {
+	if !m.isPremium() {
+		m.playbackErr = errNotPremium
+		return nil
+	}
+
+	devices, i := m.lists[viewDevices].response.Devices, m.selectedIndex(viewDevices)
+	if i < 0 || i >= len(devices) || devices[i].Active {
+		return nil
+	}
+	return transferPlaybackCmd(devices[i].ID)
+}
+
+func (m appModel) renderDevices() string {
+	view := m.renderPagedList()
+	if !m.isPremium() {
+		return view + "\n" + theme.MutedStyle.Render("  Playback control needs Spotify Premium.")
+	}
+	if m.playbackErr != nil {
+		return view + "\n  Transfer failed: " + m.playbackErr.Error()
+	}
+	return view
+}
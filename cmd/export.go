@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+)
+
+// exportVersion is the version of the archive layout. Bump it whenever a
+// field is renamed or removed so older archives can still be told apart.
+const exportVersion = 2
+
+var exportFormats = []string{"json", "zip"}
+
+const (
+	savedTracksURL    = "https://api.spotify.com/v1/me/tracks?limit=50"
+	recentlyPlayedURL = "https://api.spotify.com/v1/me/player/recently-played?limit=50"
+)
+
+// exportArchive is everything the export command writes, keyed by time range
+// where the data has one.
+type exportArchive struct {
+	Version        int                 `json:"version"`
+	ExportedAt     time.Time           `json:"exported_at"`
+	UserID         string              `json:"user_id"`
+	ToolVersion    string              `json:"tool_version"`
+	Profile        Me                  `json:"profile"`
+	TopArtists     map[string][]Artist `json:"top_artists"`
+	TopTracks      map[string][]Song   `json:"top_tracks"`
+	SavedTracks    []Song              `json:"saved_tracks"`
+	Playlists      []exportPlaylist    `json:"playlists"`
+	RecentlyPlayed []Song              `json:"recently_played"`
+}
+
+// exportPlaylist is a playlist with the URIs of its tracks, in order. An item
+// whose track is gone has an empty URI.
+type exportPlaylist struct {
+	Playlist
+	TrackURIs []string `json:"track_uris"`
+}
+
+func exportCommand() command {
+	return command{
+		name:  "export",
+		usage: "export [--output FILE] [--format json|zip]",
+		flags: func(fs *flag.FlagSet) func(args []string) error {
+			output := fs.String("output", "", "file to write (default: spotify-export-<date>.<format>)")
+			format := fs.String("format", "json", "archive format: json, or zip of JSON and CSV files")
+
+			return func(args []string) error {
+				if *format != "json" && *format != "zip" {
+					return fmt.Errorf("invalid format %q, expected json or zip", *format)
+				}
+				if *output == "" {
+					*output = fmt.Sprintf("spotify-export-%s.%s", time.Now().Format("2006-01-02"), *format)
+				}
+				return runExport(*output, *format)
+			}
+		},
+		complete: func(flag string) []string {
+			if flag == "format" {
+				return exportFormats
+			}
+			return nil
+		},
+	}
+}
+
+func runExport(output, format string) error {
+	clientID, err := GetClientID()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve client ID: %w", err)
+	}
+	if clientID == "" {
+		return fmt.Errorf("no client ID configured, run the TUI once or set SPOTIFY_CLIENT_ID")
+	}
+	if err := Login(); err != nil {
+		return fmt.Errorf("failed to log in: %w", err)
+	}
+
+	archive, err := fetchExportArchive()
+	if err != nil {
+		return err
+	}
+
+	if err := writeExportFile(output, format, archive); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported listening data to %s\n", output)
+	return nil
+}
+
+// writeExportFile writes the archive to output in the given format. Closing
+// the file flushes it, so an error closing it fails the export too.
+func writeExportFile(output, format string, archive exportArchive) (err error) {
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to write export: %w", closeErr)
+		}
+	}()
+
+	if format == "zip" {
+		err = writeExportZip(file, archive)
+	} else {
+		err = writeExportJSON(file, archive)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// fetchExportArchive fetches everything that goes into an export, reporting
+// progress on stderr. Lists too long to fetch in full are exported as far as
+// they were fetched, with a warning.
+func fetchExportArchive() (exportArchive, error) {
+	progress := func(what string) {
+		fmt.Fprintf(os.Stderr, "Fetching %s...\n", what)
+	}
+	partial := func(what string, err error) error {
+		if errors.Is(err, errPageLimit) {
+			fmt.Fprintf(os.Stderr, "Warning: %s are incomplete: %v\n", what, err)
+			return nil
+		}
+		return err
+	}
+
+	archive := exportArchive{
+		Version:     exportVersion,
+		ExportedAt:  time.Now().UTC(),
+		ToolVersion: toolVersion(),
+		TopArtists:  make(map[string][]Artist),
+		TopTracks:   make(map[string][]Song),
+	}
+
+	progress("profile")
+	me, err := fetchMe()
+	if err != nil {
+		return archive, fmt.Errorf("failed to fetch profile: %w", err)
+	}
+	archive.Profile = me
+	archive.UserID = me.ID
+
+	for _, timeRange := range timeRanges {
+		progress("top artists (" + timeRangeLabel(timeRange) + ")")
+		if archive.TopArtists[timeRange], err = fetchAllArtists(timeRange); err != nil {
+			return archive, fmt.Errorf("failed to fetch top artists: %w", err)
+		}
+
+		progress("top tracks (" + timeRangeLabel(timeRange) + ")")
+		if archive.TopTracks[timeRange], err = fetchAllSongs(timeRange); err != nil {
+			return archive, fmt.Errorf("failed to fetch top tracks: %w", err)
+		}
+	}
+
+	progress("saved tracks")
+	archive.SavedTracks, err = collectSongs(savedTracksURL)
+	if err = partial("saved tracks", err); err != nil {
+		return archive, fmt.Errorf("failed to fetch saved tracks: %w", err)
+	}
+
+	progress("playlists")
+	playlists, err := collectPlaylists(myPlaylistsURL + "?limit=50")
+	if err = partial("playlists", err); err != nil {
+		return archive, fmt.Errorf("failed to fetch playlists: %w", err)
+	}
+	token, _ := auth.GetValidAccessToken()
+	for i, playlist := range playlists {
+		progress(fmt.Sprintf("tracks of playlist %d of %d", i+1, len(playlists)))
+		// Some playlists, such as Spotify's own, cannot be read by every app,
+		// so one that fails does not fail the export
+		uris, err := fetchPlaylistTrackURIs(token, playlist.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: the tracks of %s are incomplete: %v\n", playlist.Name, err)
+		}
+		archive.Playlists = append(archive.Playlists, exportPlaylist{playlist, uris})
+	}
+
+	progress("recently played tracks")
+	archive.RecentlyPlayed, err = collectSongs(recentlyPlayedURL)
+	if err = partial("recently played tracks", err); err != nil {
+		return archive, fmt.Errorf("failed to fetch recently played tracks: %w", err)
+	}
+
+	return archive, nil
+}
+
+func writeExportJSON(w io.Writer, archive exportArchive) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(archive)
+}
+
+// writeExportZip writes the archive as export.json alongside one CSV file per
+// list, for opening in a spreadsheet.
+func writeExportZip(w io.Writer, archive exportArchive) error {
+	zw := zip.NewWriter(w)
+
+	jsonFile, err := zw.Create("export.json")
+	if err != nil {
+		return err
+	}
+	if err := writeExportJSON(jsonFile, archive); err != nil {
+		return err
+	}
+
+	for _, timeRange := range timeRanges {
+		if err := writeZipCSV(zw, "top_artists_"+timeRange+".csv", artistRecords(archive.TopArtists[timeRange])); err != nil {
+			return err
+		}
+		if err := writeZipCSV(zw, "top_tracks_"+timeRange+".csv", songRecords(archive.TopTracks[timeRange])); err != nil {
+			return err
+		}
+	}
+	if err := writeZipCSV(zw, "saved_tracks.csv", songRecords(archive.SavedTracks)); err != nil {
+		return err
+	}
+	if err := writeZipCSV(zw, "playlists.csv", playlistRecords(archive.Playlists)); err != nil {
+		return err
+	}
+	if err := writeZipCSV(zw, "playlist_tracks.csv", playlistTrackRecords(archive.Playlists)); err != nil {
+		return err
+	}
+	if err := writeZipCSV(zw, "recently_played.csv", songRecords(archive.RecentlyPlayed)); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func writeZipCSV(zw *zip.Writer, name string, records [][]string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(f)
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func artistRecords(artists []Artist) [][]string {
+	records := [][]string{{"rank", "id", "name", "genres", "popularity"}}
+	for _, a := range artists {
+		records = append(records, []string{strconv.Itoa(a.Rank), a.ID, a.Name, a.Genres, strconv.Itoa(a.Popularity)})
+	}
+	return records
+}
+
+func songRecords(songs []Song) [][]string {
+	records := [][]string{{"rank", "id", "name", "artist", "album", "popularity", "added_at", "played_at"}}
+	for _, s := range songs {
+		records = append(records, []string{strconv.Itoa(s.Rank), s.ID, s.Name, s.Artist, s.Album, strconv.Itoa(s.Popularity), s.AddedAt, s.PlayedAt})
+	}
+	return records
+}
+
+func playlistRecords(playlists []exportPlaylist) [][]string {
+	records := [][]string{{"id", "name", "owner", "track_count", "public", "collaborative", "snapshot_id", "url"}}
+	for _, p := range playlists {
+		records = append(records, []string{
+			p.ID, p.Name, p.Owner, strconv.Itoa(p.TrackCount),
+			strconv.FormatBool(p.Public), strconv.FormatBool(p.Collaborative), p.SnapshotID, p.URL,
+		})
+	}
+	return records
+}
+
+func playlistTrackRecords(playlists []exportPlaylist) [][]string {
+	records := [][]string{{"playlist_id", "position", "uri"}}
+	for _, p := range playlists {
+		for position, uri := range p.TrackURIs {
+			records = append(records, []string{p.ID, strconv.Itoa(position), uri})
+		}
+	}
+	return records
+}
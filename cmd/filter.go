@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tableRows holds every row of a list table, and the filter and sort order
+// deciding which of them the table shows and how. The table itself only ever
+// holds the shown rows, so its cursor is mapped back to an item with index.
+type tableRows struct {
+	rows     []table.Row
+	order    []int     // Indexes into rows of the shown rows
+	matches  [][][]int // Matched rune positions per shown row and cell
+	filter   textinput.Model
+	query    string
+	titles   []string         // Column titles without the sort indicator
+	keys     map[int][]string // Per column, keys to sort rows by in place of their cells
+	sortCol  int              // Column the rows are sorted by, or -1 for the order they came in
+	sortDesc bool
+}
+
+func newTableRows() tableRows {
+	ti := textinput.New()
+	ti.Placeholder = "Filter"
+	ti.Prompt = "/ "
+	ti.CharLimit = 100
+	ti.Width = 40
+	return tableRows{filter: ti, sortCol: -1}
+}
+
+// set replaces the rows of the table, keeping the filter and, where it is
+// still shown, the selected item.
+func (l *tableRows) set(t *table.Model, rows []table.Row) {
+	l.rows = rows
+	l.refresh(t, false)
+}
+
+// index returns the item shown at the given table row, or -1 if there is none.
+func (l tableRows) index(row int) int {
+	if row < 0 || row >= len(l.order) {
+		return -1
+	}
+	return l.order[row]
+}
+
+// match returns the matched rune positions of each cell of a shown row.
+func (l tableRows) match(row int) [][]int {
+	if row < 0 || row >= len(l.matches) {
+		return nil
+	}
+	return l.matches[row]
+}
+
+// refresh recomputes the shown rows. With best set, the cursor moves to the
+// best match; otherwise it stays on the selected item where possible.
+func (l *tableRows) refresh(t *table.Model, best bool) {
+	selected := l.index(t.Cursor())
+	terms := strings.Fields(strings.ToLower(l.query))
+
+	type candidate struct {
+		index   int
+		score   int
+		matches [][]int
+	}
+	var candidates []candidate
+	for i, row := range l.rows {
+		if score, positions, ok := matchRow(terms, row); ok {
+			candidates = append(candidates, candidate{i, score, positions})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if l.sortCol < 0 {
+			return l.sortDesc && candidates[i].index > candidates[j].index
+		}
+		a, okA := l.sortCell(candidates[i].index)
+		b, okB := l.sortCell(candidates[j].index)
+		if !okA || !okB {
+			return false
+		}
+		if l.sortDesc {
+			return lessCell(b, a)
+		}
+		return lessCell(a, b)
+	})
+
+	l.order, l.matches = nil, nil
+	bestRow, bestScore := 0, 0
+	shown := make([]table.Row, len(candidates))
+	for i, c := range candidates {
+		if i == 0 || c.score > bestScore {
+			bestRow, bestScore = i, c.score
+		}
+		l.order = append(l.order, c.index)
+		l.matches = append(l.matches, c.matches)
+		shown[i] = l.rows[c.index]
+	}
+	l.setSortIndicator(t)
+	t.SetRows(shown)
+
+	cursor := 0
+	if best && len(terms) > 0 {
+		cursor = bestRow
+	} else {
+		for i, index := range l.order {
+			if index == selected {
+				cursor = i
+				break
+			}
+		}
+	}
+	t.SetCursor(cursor)
+}
+
+// sortCell returns what a row is sorted by: its key for the sorted column, if
+// the column has keys, or else its cell.
+func (l tableRows) sortCell(row int) (string, bool) {
+	if l.sortCol < 0 {
+		return "", false
+	}
+	if keys, ok := l.keys[l.sortCol]; ok && row < len(keys) {
+		return keys[row], true
+	}
+	if l.sortCol >= len(l.rows[row]) {
+		return "", false
+	}
+	return l.rows[row][l.sortCol], true
+}
+
+// selectItem moves the cursor to the given item, if it is shown.
+func (l tableRows) selectItem(t *table.Model, index int) {
+	for row, i := range l.order {
+		if i == index {
+			t.SetCursor(row)
+			return
+		}
+	}
+}
+
+// setSortIndicator marks the title of the sorted column with its direction.
+func (l *tableRows) setSortIndicator(t *table.Model) {
+	columns := t.Columns()
+	if l.titles == nil {
+		for _, column := range columns {
+			l.titles = append(l.titles, column.Title)
+		}
+	}
+	for i := range columns {
+		columns[i].Title = l.titles[i]
+		if i == l.sortCol {
+			if l.sortDesc {
+				columns[i].Title += " ▼"
+			} else {
+				columns[i].Title += " ▲"
+			}
+		}
+	}
+	t.SetColumns(columns)
+}
+
+// cycleSort sorts by the next column, ascending, wrapping back to the order
+// the rows came in.
+func (l *tableRows) cycleSort(t *table.Model) {
+	l.sortCol++
+	if l.sortCol >= len(t.Columns()) {
+		l.sortCol = -1
+	}
+	l.sortDesc = false
+	l.refresh(t, false)
+}
+
+// reverseSort flips the direction of the sort.
+func (l *tableRows) reverseSort(t *table.Model) {
+	l.sortDesc = !l.sortDesc
+	l.refresh(t, false)
+}
+
+// lessCell orders two cells numerically if both are numbers, and
+// alphabetically otherwise.
+func lessCell(a, b string) bool {
+	x, errX := strconv.Atoi(a)
+	y, errY := strconv.Atoi(b)
+	if errX == nil && errY == nil {
+		return x < y
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+// filtering reports whether the filter bar is taking input.
+func (l tableRows) filtering() bool {
+	return l.filter.Focused()
+}
+
+// openFilter focuses the filter bar.
+func (l *tableRows) openFilter() tea.Cmd {
+	l.filter.Focus()
+	return textinput.Blink
+}
+
+// clearFilter closes the filter bar and shows every row again.
+func (l *tableRows) clearFilter(t *table.Model) {
+	l.filter.Blur()
+	l.filter.SetValue("")
+	l.query = ""
+	l.refresh(t, false)
+}
+
+// update handles keys while the filter bar is taking input. The arrow keys
+// still move the cursor, so a match can be picked without leaving the bar.
+func (l *tableRows) update(t *table.Model, msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		l.clearFilter(t)
+		return nil
+	case "enter":
+		l.filter.Blur()
+		return nil
+	case "up", "down", "pgup", "pgdown":
+		var cmd tea.Cmd
+		*t, cmd = t.Update(msg)
+		return cmd
+	}
+
+	var cmd tea.Cmd
+	l.filter, cmd = l.filter.Update(msg)
+	if query := l.filter.Value(); query != l.query {
+		l.query = query
+		l.refresh(t, true)
+	}
+	return cmd
+}
+
+// renderFilter renders the filter bar, or "" when no filter is set.
+func (l tableRows) renderFilter() string {
+	if !l.filtering() && l.query == "" {
+		return ""
+	}
+	count := theme.MutedStyle.Render(fmt.Sprintf("  %d of %d", len(l.order), len(l.rows)))
+	return "  " + l.filter.View() + count + "\n"
+}
+
+// currentList returns the rows and table of the current view, or nils if the
+// view is not a filterable list.
+func (m *appModel) currentList() (*tableRows, *table.Model) {
+	switch m.currentView {
+	case viewArtists:
+		return &m.artistList, &m.artistTable
+	case viewSongs:
+		return &m.songList, &m.songTable
+	}
+	if l, ok := m.lists[m.currentView]; ok {
+		return &l.list, &l.table
+	}
+	return nil, nil
+}
+
+// matchRow matches every term against the cells of a row, joined so that a
+// term may match across columns. It returns the summed score and the matched
+// rune positions of each cell.
+func matchRow(terms []string, row table.Row) (int, [][]int, bool) {
+	if len(terms) == 0 {
+		return 0, nil, true
+	}
+
+	var text []rune
+	starts := make([]int, len(row))
+	for i, cell := range row {
+		if i > 0 {
+			text = append(text, ' ')
+		}
+		starts[i] = len(text)
+		text = append(text, []rune(cell)...)
+	}
+
+	cells := make([][]int, len(row))
+	total := 0
+	for _, term := range terms {
+		score, positions, ok := fuzzyMatch([]rune(term), text)
+		if !ok {
+			return 0, nil, false
+		}
+		total += score
+		for _, pos := range positions {
+			cell := len(starts) - 1
+			for cell > 0 && starts[cell] > pos {
+				cell--
+			}
+			cells[cell] = append(cells[cell], pos-starts[cell])
+		}
+	}
+	return total, cells, true
+}
+
+// fuzzyMatch reports whether the runes of pattern, which is lower case, appear
+// in order in text, ignoring case. Of the possible matches it returns the one
+// scoring best, favouring consecutive runes and runes at the start of words.
+func fuzzyMatch(pattern, text []rune) (int, []int, bool) {
+	if len(pattern) == 0 {
+		return 0, nil, true
+	}
+
+	bestScore := 0
+	var best []int
+	for start := range text {
+		if unicode.ToLower(text[start]) != pattern[0] {
+			continue
+		}
+
+		positions := []int{start}
+		for i := start + 1; i < len(text) && len(positions) < len(pattern); i++ {
+			if unicode.ToLower(text[i]) == pattern[len(positions)] {
+				positions = append(positions, i)
+			}
+		}
+		if len(positions) < len(pattern) {
+			break // Later starts cannot match either
+		}
+
+		score := 0
+		for n, pos := range positions {
+			score++
+			if n > 0 && positions[n-1] == pos-1 {
+				score += 4
+			}
+			if pos == 0 || !unicode.IsLetter(text[pos-1]) && !unicode.IsDigit(text[pos-1]) {
+				score += 3
+			}
+		}
+		score -= (positions[len(positions)-1] - start) / 4
+
+		if best == nil || score > bestScore {
+			bestScore, best = score, positions
+		}
+	}
+
+	if best == nil {
+		return 0, nil, false
+	}
+	return bestScore, best, true
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var (
+	genreColRatios       = []float64{0.05, 0.28, 0.47, 0.1, 0.1}
+	genreArtistColRatios = []float64{0.06, 0.3, 0.52, 0.12}
+)
+
+// barEighths are the partial blocks at the end of a bar, by eighths filled.
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// genreStat is how much a genre weighs across the top artists of a time
+// range. Each artist weighs more the higher it ranks: the top of n artists
+// weighs n, the last weighs 1.
+type genreStat struct {
+	name    string
+	weight  int
+	share   float64  // Weight as a fraction of the weight of every artist
+	artists []Artist // Artists of the genre, in rank order
+}
+
+// genresState is the state of the genre views.
+type genresState struct {
+	table        table.Model // The genres of the current time range
+	artistTable  table.Model // The artists of the selected genre
+	stats        []genreStat
+	genre        genreStat // Genre shown in viewGenreArtists
+	colWidths    []int
+	artistWidths []int
+}
+
+func newGenresState() genresState {
+	return genresState{
+		table: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "#", Width: 4},
+				{Title: "Genre", Width: 30},
+				{Title: "Weight", Width: 40},
+				{Title: "Share", Width: 8},
+				{Title: "Artists", Width: 8},
+			}),
+			table.WithFocused(true),
+		),
+		artistTable: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Rank", Width: 4},
+				{Title: "Name", Width: 30},
+				{Title: "Genres", Width: 50},
+				{Title: "Weight", Width: 8},
+			}),
+			table.WithFocused(true),
+		),
+		colWidths:    calculateColumnWidths(100, genreColRatios),
+		artistWidths: calculateColumnWidths(100, genreArtistColRatios),
+	}
+}
+
+// artistWeight is the weight of the artist at the given rank of n artists.
+func artistWeight(rank, n int) int {
+	return max(n-rank+1, 1)
+}
+
+// genreBreakdown sums the weights of the artists of each genre, heaviest
+// genre first.
+func genreBreakdown(artists []Artist) []genreStat {
+	n := len(artists)
+	total := 0
+	stats := make(map[string]*genreStat)
+	for _, artist := range artists {
+		weight := artistWeight(artist.Rank, n)
+		total += weight
+		if artist.Genres == "" {
+			continue
+		}
+		for _, genre := range strings.Split(artist.Genres, ", ") {
+			stat, ok := stats[genre]
+			if !ok {
+				stat = &genreStat{name: genre}
+				stats[genre] = stat
+			}
+			stat.weight += weight
+			stat.artists = append(stat.artists, artist)
+		}
+	}
+
+	breakdown := make([]genreStat, 0, len(stats))
+	for _, stat := range stats {
+		stat.share = float64(stat.weight) / float64(total)
+		breakdown = append(breakdown, *stat)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].weight != breakdown[j].weight {
+			return breakdown[i].weight > breakdown[j].weight
+		}
+		return breakdown[i].name < breakdown[j].name
+	})
+	return breakdown
+}
+
+// renderBar renders a horizontal bar filling the given fraction of width, to
+// an eighth of a character.
+func renderBar(fraction float64, width int) string {
+	eighths := int(fraction * float64(width*8))
+	eighths = max(0, min(eighths, width*8))
+	return strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+}
+
+// setGenreRows shows the genres of the cached top artists of the current time
+// range, with bars scaled to the heaviest genre.
+func (m *appModel) setGenreRows() {
+	g := &m.genres
+	g.stats = genreBreakdown(m.rangeArtists[m.timeRange])
+
+	rows := []table.Row{}
+	for i, stat := range g.stats {
+		rows = append(rows, table.Row{
+			fmt.Sprintf("%d", i+1),
+			stat.name,
+			renderBar(float64(stat.weight)/float64(g.stats[0].weight), g.colWidths[2]),
+			fmt.Sprintf("%.1f%%", stat.share*100),
+			fmt.Sprintf("%d", len(stat.artists)),
+		})
+	}
+	g.table.SetRows(rows)
+	if g.table.Cursor() >= len(rows) {
+		g.table.SetCursor(max(len(rows)-1, 0))
+	}
+}
+
+// openGenres shows the genre breakdown of the top artists, fetching them
+// first if they are not cached.
+func (m *appModel) openGenres() tea.Cmd {
+	m.setGenreRows()
+	m.genres.table.SetCursor(0)
+	m.pushView(viewGenres)
+	return m.loadView(viewGenres)
+}
+
+// openGenreArtists shows the artists that make up the genre under the cursor.
+func (m *appModel) openGenreArtists() bool {
+	g := &m.genres
+	i := g.table.Cursor()
+	if m.currentView != viewGenres || i < 0 || i >= len(g.stats) {
+		return false
+	}
+	g.genre = g.stats[i]
+
+	n := len(m.rangeArtists[m.timeRange])
+	rows := []table.Row{}
+	for _, artist := range g.genre.artists {
+		rows = append(rows, table.Row{
+			fmt.Sprintf("%d", artist.Rank),
+			artist.Name,
+			artist.Genres,
+			fmt.Sprintf("%d", artistWeight(artist.Rank, n)),
+		})
+	}
+	g.artistTable.SetRows(rows)
+	g.artistTable.SetCursor(0)
+	m.push(navEntry{view: viewGenreArtists, title: g.genre.name})
+	return true
+}
+
+func (m appModel) renderGenres() string {
+	title := theme.TitleStyle.Render("Genres · " + timeRangeLabel(m.timeRange))
+	help := theme.HelpStyle.Render("[↑/↓] Navigate  [1] Short  [2] Medium  [3] Long  [enter] Artists  [q] Back  [H] Home")
+
+	if _, ok := m.rangeArtists[m.timeRange]; !ok {
+		if err := m.rangeErrs["artists:"+m.timeRange]; err != nil {
+			return title + "\n\n  Could not fetch top artists: " + err.Error() + "\n  Press 1, 2 or 3 to try again.\n" + help
+		}
+		return title + "\n\n  Loading top artists...\n" + help
+	}
+	if len(m.genres.stats) == 0 {
+		return title + "\n\n  None of your top artists have genres.\n" + help
+	}
+
+	summary := theme.MutedStyle.Render(fmt.Sprintf("  %d genres across %d top artists, weighted by rank", len(m.genres.stats), len(m.rangeArtists[m.timeRange])))
+	return title + "\n" + summary + "\n" + m.renderTableWithin(m.genres.table, m.genres.colWidths, tableChrome+1) + "\n" + help
+}
+
+func (m appModel) renderGenreArtists() string {
+	g := m.genres
+	title := theme.TitleStyle.Render(g.genre.name + " · " + timeRangeLabel(m.timeRange))
+	summary := theme.MutedStyle.Render(fmt.Sprintf("  %d artists, %.1f%% of the weight of your top artists", len(g.genre.artists), g.genre.share*100))
+	help := theme.HelpStyle.Render("[↑/↓] Navigate  [enter] Details  [q] Back  [H] Home")
+	return title + "\n" + summary + "\n" + m.renderTableWithin(g.artistTable, g.artistWidths, tableChrome+1) + "\n" + help
+}
@@ -1,5 +1,11 @@
 package cmd
 
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
 func calculateColumnWidths(totalWidth int, ratios []float64) []int {
 	widths := make([]int, len(ratios))
 	padding := 3 * (len(ratios) - 1) // space between columns (" | ")
@@ -18,3 +24,75 @@ func calculateColumnWidths(totalWidth int, ratios []float64) []int {
 
 	return widths
 }
+
+// formatCount formats n with thousands separators.
+func formatCount(n int) string {
+	s := fmt.Sprintf("%d", n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// formatDuration formats a duration in milliseconds as m:ss, or h:mm:ss for
+// an hour or more.
+func formatDuration(ms int) string {
+	seconds := ms / 1000
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// checkMark returns mark if set, for an indicator column.
+func checkMark(set bool, mark string) string {
+	if set {
+		return mark
+	}
+	return ""
+}
+
+// yesNo formats a flag for a table cell.
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
+// formatTimeAgo formats an RFC 3339 timestamp relative to now, such as
+// "5 min ago", falling back to the date for times over a week ago.
+func formatTimeAgo(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+
+	ago := time.Since(t)
+	switch {
+	case ago < time.Minute:
+		return "just now"
+	case ago < time.Hour:
+		return fmt.Sprintf("%d min ago", int(ago.Minutes()))
+	case ago < 24*time.Hour:
+		return fmt.Sprintf("%d h ago", int(ago.Hours()))
+	case ago < 7*24*time.Hour:
+		days := int(ago.Hours() / 24)
+		if days == 1 {
+			return "yesterday"
+		}
+		return fmt.Sprintf("%d days ago", days)
+	}
+	return t.Local().Format("2006-01-02")
+}
+
+// timestampKey returns an RFC 3339 timestamp as milliseconds since the epoch,
+// to sort a column of formatTimeAgo cells by. A missing timestamp sorts
+// first.
+func timestampKey(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return "0"
+	}
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	"github.com/bytegrunt/go-spotify-me/internal/logging"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	savedAlbumsURL     = "https://api.spotify.com/v1/me/albums?limit=50"
+	followedArtistsURL = "https://api.spotify.com/v1/me/following?type=artist&limit=50"
+)
+
+// containsBatchSize is the most IDs the /contains endpoints accept at once.
+const containsBatchSize = 50
+
+// fetchContains asks a /contains endpoint which of ids it holds, in batches,
+// and returns the set of IDs that are present.
+func fetchContains(endpoint string, ids []string) (map[string]bool, error) {
+	token, _ := auth.GetValidAccessToken()
+	contains := make(map[string]bool)
+
+	for start := 0; start < len(ids); start += containsBatchSize {
+		batch := ids[start:min(start+containsBatchSize, len(ids))]
+		sep := "?"
+		if strings.Contains(endpoint, "?") {
+			sep = "&"
+		}
+
+		response, err := makeAPIListRequest(token, endpoint+sep+"ids="+strings.Join(batch, ","))
+		if err != nil {
+			return nil, err
+		}
+		for i, present := range response {
+			if present, _ := present.(bool); present && i < len(batch) {
+				contains[batch[i]] = true
+			}
+		}
+	}
+
+	return contains, nil
+}
+
+// fetchSavedTracks returns which of the track IDs are in the user's Liked Songs.
+func fetchSavedTracks(ids []string) (map[string]bool, error) {
+	return fetchContains("https://api.spotify.com/v1/me/tracks/contains", ids)
+}
+
+// fetchFollowedArtists returns which of the artist IDs the user follows.
+func fetchFollowedArtists(ids []string) (map[string]bool, error) {
+	return fetchContains("https://api.spotify.com/v1/me/following/contains?type=artist", ids)
+}
+
+// containsMsg carries which of the shown songs are liked, or which of the
+// shown artists are followed.
+type containsMsg struct {
+	artists  bool // The IDs are of artists rather than songs
+	contains map[string]bool
+	err      error
+}
+
+func fetchSavedTracksCmd(songs []Song) tea.Cmd {
+	ids := make([]string, len(songs))
+	for i, song := range songs {
+		ids[i] = song.ID
+	}
+	return func() tea.Msg {
+		saved, err := fetchSavedTracks(ids)
+		return containsMsg{false, saved, err}
+	}
+}
+
+func fetchFollowedArtistsCmd(artists []Artist) tea.Cmd {
+	ids := make([]string, len(artists))
+	for i, artist := range artists {
+		ids[i] = artist.ID
+	}
+	return func() tea.Msg {
+		followed, err := fetchFollowedArtists(ids)
+		return containsMsg{true, followed, err}
+	}
+}
+
+// storeContains records which songs are liked or artists followed.
+func (m *appModel) storeContains(msg containsMsg) {
+	if msg.err != nil {
+		logging.DebugLog("Failed to fetch library state: %v", msg.err)
+		return
+	}
+
+	target := m.likedSongs
+	if msg.artists {
+		target = m.followedArtists
+	}
+	for id, present := range msg.contains {
+		target[id] = present
+	}
+
+	if msg.artists {
+		m.setArtistRows()
+	} else {
+		m.setSongRows()
+	}
+}
+
+type libraryToggledMsg struct {
+	artists bool
+	id      string
+	name    string
+	added   bool // Whether the item was liked or followed, rather than removed
+	err     error
+}
+
+// toggleLibraryCmd likes or unlikes a song, or follows or unfollows an artist.
+func toggleLibraryCmd(artists bool, id, name string, add bool) tea.Cmd {
+	return func() tea.Msg {
+		endpoint := "https://api.spotify.com/v1/me/tracks"
+		if artists {
+			endpoint = "https://api.spotify.com/v1/me/following?type=artist"
+		}
+		method := "PUT"
+		if !add {
+			method = "DELETE"
+		}
+
+		token, _ := auth.GetValidAccessToken()
+		_, err := doAPIRequest(token, method, endpoint, map[string]interface{}{"ids": []string{id}})
+		return libraryToggledMsg{artists, id, name, add, err}
+	}
+}
+
+// toggleSelected likes or unlikes the song, or follows or unfollows the
+// artist, under the cursor of the top lists.
+func (m appModel) toggleSelected() tea.Cmd {
+	switch m.currentView {
+	case viewSongs:
+		if i := m.songList.index(m.songTable.Cursor()); i >= 0 && i < len(m.songs.Songs) {
+			song := m.songs.Songs[i]
+			return toggleLibraryCmd(false, song.ID, song.Name, !m.likedSongs[song.ID])
+		}
+	case viewArtists:
+		if i := m.artistList.index(m.artistTable.Cursor()); i >= 0 && i < len(m.artists.Artists) {
+			artist := m.artists.Artists[i]
+			return toggleLibraryCmd(true, artist.ID, artist.Name, !m.followedArtists[artist.ID])
+		}
+	}
+	return nil
+}
+
+// libraryToggled records a like or follow and confirms it.
+func (m *appModel) libraryToggled(msg libraryToggledMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.showToast("Could not update " + msg.name + ": " + msg.err.Error())
+	}
+
+	view, target := viewLiked, m.likedSongs
+	if msg.artists {
+		view, target = viewFollowed, m.followedArtists
+	}
+	target[msg.id] = msg.added
+	if count, ok := m.libraryCounts[view]; ok {
+		if msg.added {
+			m.libraryCounts[view] = count + 1
+		} else {
+			m.libraryCounts[view] = count - 1
+		}
+	}
+
+	if msg.artists {
+		m.setArtistRows()
+	} else {
+		m.setSongRows()
+	}
+
+	switch {
+	case msg.artists && msg.added:
+		return m.showToast("Following " + msg.name)
+	case msg.artists:
+		return m.showToast("Unfollowed " + msg.name)
+	case msg.added:
+		return m.showToast("Added " + msg.name + " to Liked Songs")
+	}
+	return m.showToast("Removed " + msg.name + " from Liked Songs")
+}
+
+var likedSource = listSource{
+	url: savedTracksURL,
+	columns: []table.Column{
+		{Title: "#", Width: 4},
+		{Title: "Name", Width: 40},
+		{Title: "Artist", Width: 20},
+		{Title: "Album", Width: 30},
+		{Title: "Added", Width: 16},
+	},
+	ratios: []float64{0.05, 0.33, 0.2, 0.24, 0.18},
+	fetch:  fetchSongsPage,
+	rows: func(response APIResponse, offset int) []table.Row {
+		rows := []table.Row{}
+		for i, song := range response.Songs {
+			rows = append(rows, table.Row{
+				fmt.Sprintf("%d", offset+i+1),
+				song.Name,
+				song.Artist,
+				song.Album,
+				formatTimeAgo(song.AddedAt),
+			})
+		}
+		return rows
+	},
+	sortKeys: func(response APIResponse) map[int][]string {
+		added := make([]string, len(response.Songs))
+		for i, song := range response.Songs {
+			added[i] = timestampKey(song.AddedAt)
+		}
+		return map[int][]string{4: added}
+	},
+}
+
+var savedAlbumsSource = listSource{
+	url: savedAlbumsURL,
+	columns: []table.Column{
+		{Title: "#", Width: 4},
+		{Title: "Name", Width: 40},
+		{Title: "Artist", Width: 30},
+		{Title: "Released", Width: 12},
+		{Title: "Tracks", Width: 8},
+	},
+	ratios: []float64{0.05, 0.4, 0.3, 0.15, 0.1},
+	fetch:  fetchAlbumsPage,
+	rows: func(response APIResponse, offset int) []table.Row {
+		rows := []table.Row{}
+		for i, album := range response.Albums {
+			rows = append(rows, table.Row{
+				fmt.Sprintf("%d", offset+i+1),
+				album.Name,
+				album.Artist,
+				album.ReleaseDate,
+				fmt.Sprintf("%d", album.TotalTracks),
+			})
+		}
+		return rows
+	},
+}
+
+var followedSource = listSource{
+	url: followedArtistsURL,
+	columns: []table.Column{
+		{Title: "#", Width: 4},
+		{Title: "Name", Width: 40},
+		{Title: "Genres", Width: 50},
+		{Title: "Followers", Width: 12},
+		{Title: "Popularity", Width: 10},
+	},
+	ratios: []float64{0.05, 0.3, 0.38, 0.12, 0.15},
+	fetch:  fetchFollowedPage,
+	rows: func(response APIResponse, offset int) []table.Row {
+		rows := []table.Row{}
+		for i, artist := range response.Artists {
+			rows = append(rows, table.Row{
+				fmt.Sprintf("%d", offset+i+1),
+				artist.Name,
+				artist.Genres,
+				fmt.Sprintf("%d", artist.Followers),
+				fmt.Sprintf("%d", artist.Popularity),
+			})
+		}
+		return rows
+	},
+}
+
+func fetchAlbumsPage(url string) (APIResponse, error) {
+	token, _ := auth.GetValidAccessToken()
+	response, err := MakeAPIRequest(token, url)
+	if err != nil {
+		return APIResponse{}, err
+	}
+
+	var albums []Album
+	if items, ok := response["items"].([]interface{}); ok {
+		albums = parseAlbumItems(items)
+	}
+	next, _ := response["next"].(string)
+	prev, _ := response["previous"].(string)
+
+	return APIResponse{
+		Albums: albums,
+		Next:   next,
+		Prev:   prev,
+	}, nil
+}
+
+// fetchFollowedPage fetches a page of followed artists. Unlike the other
+// library endpoints, it wraps the page in an "artists" object and pages with
+// an after cursor, so there is no previous link.
+func fetchFollowedPage(url string) (APIResponse, error) {
+	token, _ := auth.GetValidAccessToken()
+	response, err := MakeAPIRequest(token, url)
+	if err != nil {
+		return APIResponse{}, err
+	}
+
+	page, _ := response["artists"].(map[string]interface{})
+	next, _ := page["next"].(string)
+
+	return APIResponse{
+		Artists: parseArtists(page),
+		Next:    next,
+	}, nil
+}
+
+// libraryCountsMsg carries the number of items in each library view.
+type libraryCountsMsg struct {
+	counts map[viewType]int
+}
+
+// fetchLibraryCountsCmd fetches the size of the user's library for the menu.
+// A count that cannot be fetched is left out.
+func fetchLibraryCountsCmd() tea.Cmd {
+	return func() tea.Msg {
+		token, _ := auth.GetValidAccessToken()
+		counts := make(map[viewType]int)
+
+		for view, url := range map[viewType]string{
+			viewLiked:       savedTracksURL,
+			viewSavedAlbums: savedAlbumsURL,
+			viewFollowed:    followedArtistsURL,
+		} {
+			response, err := MakeAPIRequest(token, strings.Replace(url, "limit=50", "limit=1", 1))
+			if err != nil {
+				logging.DebugLog("Failed to fetch library count: %v", err)
+				continue
+			}
+			if page, ok := response["artists"].(map[string]interface{}); ok {
+				response = page
+			}
+			if total, ok := response["total"].(float64); ok {
+				counts[view] = int(total)
+			}
+		}
+
+		return libraryCountsMsg{counts}
+	}
+}
@@ -19,6 +19,40 @@ import (
 
 var logger *zap.Logger
 
+// scopes are the permissions requested when logging in
+var scopes = []string{
+	"user-read-private",
+	"user-read-email",
+	"user-top-read",
+	"user-library-read",
+	"user-library-modify",
+	"user-follow-read",
+	"user-follow-modify",
+	"playlist-read-private",
+	"playlist-modify-private",
+	"playlist-modify-public",
+	"user-read-recently-played",
+	"user-read-playback-state",
+	"user-read-currently-playing",
+	"user-modify-playback-state",
+}
+
+// missingScopes returns the scopes the saved token was not granted.
+func missingScopes() []string {
+	granted := make(map[string]bool)
+	for _, scope := range auth.GrantedScopes() {
+		granted[scope] = true
+	}
+
+	var missing []string
+	for _, scope := range scopes {
+		if !granted[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
 func InitializeLogger() error {
 	var err error
 	logger, err = zap.NewProduction()
@@ -47,7 +81,7 @@ func Login() error {
 	}
 
 	_, isValid := auth.GetValidAccessToken()
-	if isValid {
+	if isValid && len(missingScopes()) == 0 {
 		return nil
 	}
 
@@ -86,11 +120,17 @@ func Login() error {
 	if refreshToken != "" {
 		logger.Debug("Using existing refresh token to get a new access token.")
 		err := auth.RefreshAccessToken(authConfig, refreshToken)
-		if err == nil {
+		if err == nil && len(missingScopes()) == 0 {
 			return nil // Successfully refreshed the token, exit the command
 		}
-		logger.Debug("Failed to refresh access token", zap.Error(err))
-		logger.Debug("Falling back to regular login flow.")
+		if err != nil {
+			logger.Debug("Failed to refresh access token", zap.Error(err))
+			logger.Debug("Falling back to regular login flow.")
+		} else {
+			// Tokens granted before a scope was added never gain it, so the
+			// user has to authorize the app again
+			logger.Info("New permissions are needed, authorize the app again in your browser.", zap.Strings("missing", missingScopes()))
+		}
 	}
 
 	// Generate the code verifier and code challenge
@@ -98,8 +138,8 @@ func Login() error {
 	codeChallenge := auth.GenerateCodeChallenge(codeVerifier)
 
 	// Generate the authorization URL
-	authURLWithParams := fmt.Sprintf("%s?client_id=%s&response_type=code&redirect_uri=%s&scope=user-read-private user-read-email user-top-read&code_challenge=%s&code_challenge_method=S256",
-		authConfig.AuthURL, url.QueryEscape(authConfig.ClientID), authConfig.RedirectURI, codeChallenge)
+	authURLWithParams := fmt.Sprintf("%s?client_id=%s&response_type=code&redirect_uri=%s&scope=%s&code_challenge=%s&code_challenge_method=S256",
+		authConfig.AuthURL, url.QueryEscape(authConfig.ClientID), authConfig.RedirectURI, strings.Join(scopes, " "), codeChallenge)
 
 	logger.Debug("Generated authorization URL", zap.String("url", authURLWithParams))
 
@@ -6,11 +6,12 @@ import (
 
 // Me represents the user information from the /me endpoint
 type Me struct {
-	Country     string
-	DisplayName string
-	Email       string
-	Product     string
-	ProfileURL  string
+	ID          string `json:"id"`
+	Country     string `json:"country"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email"`
+	Product     string `json:"product"`
+	ProfileURL  string `json:"profile_url"`
 }
 
 // fetchMe fetches the user's information from the /me endpoint
@@ -21,6 +22,7 @@ func fetchMe() (Me, error) {
 		return Me{}, err
 	}
 
+	id, _ := response["id"].(string)
 	country, _ := response["country"].(string)
 	displayName, _ := response["display_name"].(string)
 	email, _ := response["email"].(string)
@@ -29,6 +31,7 @@ func fetchMe() (Me, error) {
 	profileURL, _ := externalURLs["spotify"].(string)
 
 	return Me{
+		ID:          id,
 		Country:     country,
 		DisplayName: displayName,
 		Email:       email,
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+)
+
+// navEntry is a view on the navigation stack, with the state needed to show
+// it again when navigating back to it. The top artist and song lists keep
+// their state on appModel, so only detail views carry state here.
+type navEntry struct {
+	view   viewType
+	title  string        // Breadcrumb label
+	artist *artistDetail // For viewArtistDetail
+	album  *albumDetail  // For viewAlbum
+	track  Song          // For viewTrackDetail
+}
+
+// id identifies the item a detail entry shows, or "" for list views.
+func (e navEntry) id() string {
+	switch {
+	case e.artist != nil:
+		return e.artist.artist.ID
+	case e.album != nil:
+		return e.album.album.ID
+	case e.view == viewTrackDetail:
+		return e.track.ID
+	}
+	return ""
+}
+
+// viewTitles are the breadcrumb labels of views that are not about one item.
+var viewTitles = map[viewType]string{
+	viewMenu:            "Menu",
+	viewArtists:         "Top Artists",
+	viewSongs:           "Top Songs",
+	viewRecent:          "Recently Played",
+	viewLiked:           "Liked Songs",
+	viewSavedAlbums:     "Saved Albums",
+	viewFollowed:        "Followed Artists",
+	viewPlaylists:       "Playlists",
+	viewNowPlaying:      "Now Playing",
+	viewDevices:         "Devices",
+	viewQueue:           "Up Next",
+	viewRecommendations: "Recommendations",
+	viewGenres:          "Genres",
+	viewCompare:         "Compare",
+	viewSearch:          "Search",
+	viewEnterClientID:   "Client ID",
+}
+
+// top returns the entry of the current view.
+func (m appModel) top() *navEntry {
+	return &m.stack[len(m.stack)-1]
+}
+
+// push opens a view on top of the current one.
+func (m *appModel) push(entry navEntry) {
+	if entry.title == "" {
+		entry.title = viewTitles[entry.view]
+	}
+	m.stack = append(m.stack, entry)
+	m.currentView = entry.view
+	m.focusView(entry.view)
+}
+
+// open shows a fetched view. Views of the same kind share their state, so if
+// the view is already on the stack this goes back to it instead of stacking
+// it again.
+func (m *appModel) open(entry navEntry) {
+	for i, e := range m.stack {
+		if e.view != entry.view {
+			continue
+		}
+		for len(m.stack) > i+1 && m.pop() {
+		}
+		if entry.title != "" {
+			m.top().title = entry.title
+		}
+		return
+	}
+	m.push(entry)
+}
+
+// pushView opens a view that is not about one item.
+func (m *appModel) pushView(view viewType) {
+	m.push(navEntry{view: view})
+}
+
+// pop goes back to the previous view. It reports false when already at the
+// root view.
+func (m *appModel) pop() bool {
+	if len(m.stack) <= 1 {
+		return false
+	}
+
+	switch m.currentView {
+	case viewArtists:
+		m.artistTable.Blur()
+	case viewSongs:
+		m.songTable.Blur()
+	default:
+		if l, ok := m.lists[m.currentView]; ok {
+			l.table.Blur()
+		}
+	}
+
+	m.stack = m.stack[:len(m.stack)-1]
+	m.currentView = m.top().view
+	m.focusView(m.currentView)
+	return true
+}
+
+// home goes back to the root view.
+func (m *appModel) home() {
+	for m.pop() {
+	}
+}
+
+// backTo pops back to the previous view if it shows the given item, so that
+// following a link back to where the user came from does not grow the stack.
+func (m *appModel) backTo(view viewType, id string) bool {
+	if len(m.stack) < 2 {
+		return false
+	}
+	prev := m.stack[len(m.stack)-2]
+	if prev.view != view || prev.id() != id {
+		return false
+	}
+	return m.pop()
+}
+
+// renderBreadcrumbs renders the path from the root view to the current one.
+func (m appModel) renderBreadcrumbs() string {
+	if len(m.stack) < 2 {
+		return ""
+	}
+
+	crumbs := make([]string, len(m.stack))
+	for i, entry := range m.stack {
+		crumbs[i] = entry.title
+	}
+	return theme.MutedStyle.Render("  "+strings.Join(crumbs, " › ")) + "\n"
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// listPageSize is the number of items per page of the paged lists, which is
+// also the limit set in their URLs.
+const listPageSize = 50
+
+var pagedFooter = theme.HelpStyle.Render("[↑/↓] Navigate  [←] Prev Page  [→] Next Page  [/] Filter  [o/O] Sort  [enter] Open  [q] Back  [H] Home")
+
+// listSource describes where a paged list view gets its items from and how
+// they are shown.
+type listSource struct {
+	url     string // URL of the first page
+	columns []table.Column
+	ratios  []float64
+	fetch   func(url string) (APIResponse, error)
+	rows    func(response APIResponse, offset int) []table.Row // Offset is the number of items on earlier pages
+	footer  string                                             // Help line, pagedFooter if empty
+	// sortKeys returns, per column, what to sort the rows by where that is not
+	// the shown cell, such as the timestamp behind "5 min ago". It may be nil.
+	sortKeys func(response APIResponse) map[int][]string
+}
+
+// listSources are the paged list views, other than the top artists and songs.
+var listSources = map[viewType]listSource{
+	viewRecent:          recentSource,
+	viewLiked:           likedSource,
+	viewSavedAlbums:     savedAlbumsSource,
+	viewFollowed:        followedSource,
+	viewPlaylists:       playlistsSource,
+	viewPlaylistTracks:  playlistTracksSource,
+	viewDevices:         devicesSource,
+	viewQueue:           queueSource,
+	viewPlaylistPicker:  playlistPickerSource,
+	viewRecommendations: recommendationsSource,
+}
+
+// pagedList is the state of a paged list view. The URLs of the pages seen are
+// kept so that lists paged with cursors, which only link to the next page, can
+// be paged back through as well.
+type pagedList struct {
+	response APIResponse
+	pages    []string // URLs of the pages up to the current one
+	title    string   // Breadcrumb label, if not the view's title
+	table    table.Model
+	list     tableRows
+	widths   []int
+}
+
+// switchToPageMsg carries a page of a paged list. Page is the page's position
+// in the list, counted from the first. From is the view the page was fetched
+// from; if the user has since moved on to another view, the page is dropped.
+type switchToPageMsg struct {
+	view     viewType
+	response APIResponse
+	url      string
+	page     int
+	from     viewType
+}
+
+// fetchPageCmd fetches a page of a paged list and switches to its view from
+// the given view.
+func fetchPageCmd(view viewType, url string, page int, from viewType) tea.Cmd {
+	fetch := listSources[view].fetch
+	return func() tea.Msg {
+		response, err := fetch(url)
+		if err != nil {
+			return errMsg{err}
+		}
+		return switchToPageMsg{view, response, url, page, from}
+	}
+}
+
+func newPagedLists() map[viewType]*pagedList {
+	lists := make(map[viewType]*pagedList)
+	for view, source := range listSources {
+		lists[view] = &pagedList{
+			table:  table.New(table.WithColumns(source.columns), table.WithFocused(false)),
+			list:   newTableRows(),
+			widths: calculateColumnWidths(100, source.ratios),
+		}
+	}
+	return lists
+}
+
+// showPage shows a fetched page of a paged list.
+func (m *appModel) showPage(msg switchToPageMsg) {
+	if m.currentView != msg.view && m.currentView != msg.from {
+		return
+	}
+
+	l := m.lists[msg.view]
+	l.response = msg.response
+	l.pages = append(l.pages[:min(msg.page, len(l.pages))], msg.url)
+	if msg.page > 0 {
+		l.response.Prev = l.pages[msg.page-1]
+	}
+
+	l.setRows(listSources[msg.view], msg.page*listPageSize)
+	l.table.SetCursor(0)
+	if m.currentView != msg.view {
+		m.open(navEntry{view: msg.view, title: l.title})
+	}
+}
+
+// replacePage shows a refetched version of the current page of a paged list,
+// keeping the cursor on the selected item.
+func (m *appModel) replacePage(view viewType, response APIResponse) {
+	l := m.lists[view]
+	response.Prev = l.response.Prev
+	l.response = response
+	l.setRows(listSources[view], max(len(l.pages)-1, 0)*listPageSize)
+}
+
+// setRows shows the rows of the current response, with their sort keys.
+func (l *pagedList) setRows(source listSource, offset int) {
+	l.list.keys = nil
+	if source.sortKeys != nil {
+		l.list.keys = source.sortKeys(l.response)
+	}
+	l.list.set(&l.table, source.rows(l.response, offset))
+}
+
+// turnPage fetches the next or previous page of the current paged list.
+func (m appModel) turnPage(next bool) tea.Cmd {
+	l, ok := m.lists[m.currentView]
+	if !ok {
+		return nil
+	}
+
+	page := len(l.pages) - 1
+	if next && l.response.Next != "" {
+		return fetchPageCmd(m.currentView, l.response.Next, page+1, m.currentView)
+	}
+	if !next && l.response.Prev != "" {
+		return fetchPageCmd(m.currentView, l.response.Prev, page-1, m.currentView)
+	}
+	return nil
+}
+
+// selectedIndex returns the index into the current page of the item under the
+// cursor of a paged list view, or -1 if there is none.
+func (m appModel) selectedIndex(view viewType) int {
+	l, ok := m.lists[view]
+	if !ok || m.currentView != view {
+		return -1
+	}
+	return l.list.index(l.table.Cursor())
+}
+
+func (m appModel) renderPagedList() string {
+	l := m.lists[m.currentView]
+	source := listSources[m.currentView]
+
+	title := m.top().title
+	if count, ok := m.libraryCounts[m.currentView]; ok {
+		title += " (" + formatCount(count) + ")"
+	}
+
+	footer := source.footer
+	if footer == "" {
+		footer = pagedFooter
+	}
+	return theme.TitleStyle.Render(title) + "\n" + m.renderList(l.list, l.table, l.widths) + "\n" + footer
+}
@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const playerURL = "https://api.spotify.com/v1/me/player"
+
+// playerSettleDelay is how long to wait after a playback command before
+// fetching the player state, which Spotify updates with a short lag.
+const playerSettleDelay = 300 * time.Millisecond
+
+// Steps of the seek and volume keys
+const (
+	seekStepMs    = 10000
+	volumeStepPct = 10
+)
+
+var (
+	errNotPremium      = errors.New("playback control needs Spotify Premium")
+	errVolumeUnknown   = errors.New("the volume of the device is not known, so it cannot be changed")
+	errDurationUnknown = errors.New("the length of what is playing is not known, so it cannot be seeked")
+)
+
+// playbackState is what the user's player is doing.
+type playbackState struct {
+	active     bool // False when nothing is playing on any device
+	playing    bool
+	track      Song
+	progressMs int
+	device     string
+	deviceType string
+	volume     int  // Percent; only set if hasVolume
+	hasVolume  bool // False when nothing is playing or the device has no volume control
+	shuffle    bool
+	repeat     string    // off, track or context
+	fetchedAt  time.Time // When progressMs was current
+}
+
+// progressNow estimates how far the track has played by now, up to its end
+// if its length is known.
+func (p playbackState) progressNow() int {
+	progress := p.progressMs
+	if p.playing {
+		progress += int(time.Since(p.fetchedAt).Milliseconds())
+	}
+	if p.track.DurationMs > 0 {
+		return min(progress, p.track.DurationMs)
+	}
+	return progress
+}
+
+type playbackMsg struct {
+	state playbackState
+	err   error
+}
+
+// fetchPlaybackCmd fetches the player state.
+func fetchPlaybackCmd() tea.Cmd {
+	return func() tea.Msg {
+		state, err := fetchPlaybackState()
+		return playbackMsg{state, err}
+	}
+}
+
+// fetchPlaybackState fetches the player state. It asks for episodes too,
+// which otherwise come without an item, as ads do.
+func fetchPlaybackState() (playbackState, error) {
+	token, _ := auth.GetValidAccessToken()
+	body, err := doAPIRequest(token, "GET", playerURL+"?additional_types=episode", nil)
+	if err != nil {
+		return playbackState{}, err
+	}
+	return parsePlaybackState(body)
+}
+
+// parsePlaybackState parses the player state.
+func parsePlaybackState(body []byte) (playbackState, error) {
+	// Spotify answers 204 with no body when nothing is playing
+	if len(body) == 0 {
+		return playbackState{fetchedAt: time.Now()}, nil
+	}
+	response, err := parseJSONObject(body)
+	if err != nil {
+		return playbackState{}, err
+	}
+
+	state := playbackState{active: true, fetchedAt: time.Now()}
+	state.playing, _ = response["is_playing"].(bool)
+	state.shuffle, _ = response["shuffle_state"].(bool)
+	state.repeat, _ = response["repeat_state"].(string)
+	progress, _ := response["progress_ms"].(float64)
+	state.progressMs = int(progress)
+
+	if device, ok := response["device"].(map[string]interface{}); ok {
+		state.device, _ = device["name"].(string)
+		state.deviceType, _ = device["type"].(string)
+		volume, ok := device["volume_percent"].(float64)
+		state.volume, state.hasVolume = int(volume), ok
+	}
+	if item, ok := response["item"].(map[string]interface{}); ok {
+		if songs := parseSongItems([]interface{}{item}, 0); len(songs) > 0 {
+			state.track = songs[0]
+		}
+	}
+
+	return state, nil
+}
+
+// playerCmd sends a playback command and then fetches the new player state.
+func playerCmd(method, url string, payload interface{}) tea.Cmd {
+	return func() tea.Msg {
+		token, _ := auth.GetValidAccessToken()
+		if _, err := doAPIRequest(token, method, url, payload); err != nil {
+			return playbackMsg{err: err}
+		}
+
+		time.Sleep(playerSettleDelay)
+		state, err := fetchPlaybackState()
+		return playbackMsg{state, err}
+	}
+}
+
+// volumeCmd changes the volume by step percent. The volume is fetched first,
+// as the one last shown may be stale or not known at all.
+func volumeCmd(step int) tea.Cmd {
+	return func() tea.Msg {
+		state, err := fetchPlaybackState()
+		if err != nil {
+			return playbackMsg{err: err}
+		}
+		if !state.hasVolume {
+			return playbackMsg{state, errVolumeUnknown}
+		}
+
+		volume := max(0, min(state.volume+step, 100))
+		return playerCmd("PUT", fmt.Sprintf("%s/volume?volume_percent=%d", playerURL, volume), nil)()
+	}
+}
+
+// isPremium reports whether the account may control playback.
+func (m appModel) isPremium() bool {
+	return m.me.Product == "premium"
+}
+
+// updatePlayback handles the playback keys, which work from any view. It
+// reports false for keys that are not playback keys.
+func (m *appModel) updatePlayback(msg tea.KeyMsg) (tea.Cmd, bool) {
+	key := msg.String()
+	switch key {
+	case "n", "N":
+		if m.currentView == viewNowPlaying {
+			return nil, true
+		}
+		m.pushView(viewNowPlaying)
+		return fetchPlaybackCmd(), true
+	case "D":
+		if m.currentView == viewDevices {
+			return nil, true
+		}
+		return m.loadView(viewDevices), true
+	case "U":
+		if m.currentView == viewQueue {
+			return nil, true
+		}
+		return m.loadView(viewQueue), true
+	case "e":
+		return m.queueSelected(), true
+	case " ", ">", "<", "]", "[", "+", "=", "-":
+	case "P":
+		// P on the menu opens the playlists
+		if m.currentView == viewMenu {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	if !m.isPremium() {
+		return m.showToast(errNotPremium.Error()), true
+	}
+
+	p := m.playback
+	switch key {
+	case " ":
+		if p.playing {
+			return playerCmd("PUT", playerURL+"/pause", nil), true
+		}
+		return playerCmd("PUT", playerURL+"/play", nil), true
+	case ">":
+		return playerCmd("POST", playerURL+"/next", nil), true
+	case "<":
+		return playerCmd("POST", playerURL+"/previous", nil), true
+	case "]", "[":
+		// Without a length, as during an ad, there is nothing to seek within
+		if p.track.DurationMs <= 0 {
+			return m.showToast(errDurationUnknown.Error()), true
+		}
+
+		// Seek from where the track has played to by now, not the last poll
+		position := p.progressNow() + seekStepMs
+		if key == "[" {
+			position = p.progressNow() - seekStepMs
+		}
+		position = max(0, min(position, p.track.DurationMs))
+		return playerCmd("PUT", fmt.Sprintf("%s/seek?position_ms=%d", playerURL, position), nil), true
+	case "+", "=", "-":
+		if key == "-" {
+			return volumeCmd(-volumeStepPct), true
+		}
+		return volumeCmd(volumeStepPct), true
+	case "P":
+		payload, ok := m.playSelectedPayload()
+		if !ok {
+			return nil, true
+		}
+		return playerCmd("PUT", playerURL+"/play", payload), true
+	}
+	return nil, true
+}
+
+// playSelectedPayload returns the body of the play request for the row under
+// the cursor. Tracks of an album or playlist play in its context, so playback
+// carries on with the tracks after them.
+func (m appModel) playSelectedPayload() (map[string]interface{}, bool) {
+	if song, ok := m.selectedSong(); ok && song.URI != "" {
+		switch m.currentView {
+		case viewPlaylistTracks:
+			return map[string]interface{}{
+				"context_uri": "spotify:playlist:" + m.playlist.ID,
+				"offset":      map[string]string{"uri": song.URI},
+			}, true
+		case viewAlbum:
+			return map[string]interface{}{
+				"context_uri": "spotify:album:" + m.top().album.album.ID,
+				"offset":      map[string]string{"uri": song.URI},
+			}, true
+		}
+		return map[string]interface{}{"uris": []string{song.URI}}, true
+	}
+
+	if context := m.selectedContextURI(); context != "" {
+		return map[string]interface{}{"context_uri": context}, true
+	}
+	return nil, false
+}
+
+// selectedContextURI returns the URI of the artist, album or playlist under
+// the cursor, or "" if there is none.
+func (m appModel) selectedContextURI() string {
+	switch m.currentView {
+	case viewArtists:
+		if i := m.artistList.index(m.artistTable.Cursor()); i >= 0 && i < len(m.artists.Artists) {
+			return "spotify:artist:" + m.artists.Artists[i].ID
+		}
+	case viewFollowed:
+		if artists, i := m.lists[viewFollowed].response.Artists, m.selectedIndex(viewFollowed); i >= 0 && i < len(artists) {
+			return "spotify:artist:" + artists[i].ID
+		}
+	case viewSavedAlbums:
+		if albums, i := m.lists[viewSavedAlbums].response.Albums, m.selectedIndex(viewSavedAlbums); i >= 0 && i < len(albums) {
+			return "spotify:album:" + albums[i].ID
+		}
+	case viewPlaylists:
+		if playlists, i := m.lists[viewPlaylists].response.Playlists, m.selectedIndex(viewPlaylists); i >= 0 && i < len(playlists) {
+			return "spotify:playlist:" + playlists[i].ID
+		}
+	case viewArtistDetail:
+		d := m.top().artist
+		if i := d.tables[d.section].Cursor(); d.section == artistAlbums && i >= 0 && i < len(d.albums) {
+			return "spotify:album:" + d.albums[i].ID
+		} else if d.section == artistRelated && i >= 0 && i < len(d.related) {
+			return "spotify:artist:" + d.related[i].ID
+		}
+	}
+	return ""
+}
+
+// renderProgressBar renders how far a track has played as a bar of the given
+// width between the elapsed and total time.
+func renderProgressBar(progressMs, durationMs, width int) string {
+	filled := 0
+	if durationMs > 0 {
+		filled = min(width, width*progressMs/durationMs)
+	}
+	bar := theme.HighlightStyle.Render(strings.Repeat("━", filled)) + theme.MutedStyle.Render(strings.Repeat("─", width-filled))
+	return formatDuration(progressMs) + " " + bar + " " + formatDuration(durationMs)
+}
+
+func (m appModel) renderNowPlaying() string {
+	title := theme.TitleStyle.Render("Now Playing") + "\n"
+	help := theme.HelpStyle.Render("[space] Play/Pause  [<] Previous  [>] Next  [[/]] Seek  [-/+] Volume  [P] Play Selected Row  [D] Devices  [U] Up Next  [e] Queue Selected  [q] Back")
+
+	var lines []string
+	p := m.playback
+	if !p.active {
+		lines = append(lines, "Nothing is playing.")
+	} else {
+		t := p.track
+		state := "Paused"
+		if p.playing {
+			state = "Playing"
+		}
+		shuffle := "Off"
+		if p.shuffle {
+			shuffle = "On"
+		}
+		volume := "-"
+		if p.hasVolume {
+			volume = fmt.Sprintf("%d%%", p.volume)
+		}
+
+		lines = append(lines,
+			theme.HighlightStyle.Render(t.Name),
+			strings.Join(t.Artists, ", ")+" · "+t.Album,
+			"",
+			renderProgressBar(p.progressNow(), t.DurationMs, max(m.windowSize.Width-40, 10)),
+			"",
+			fmt.Sprintf("%s on %s (%s) · Volume %s", state, p.device, p.deviceType, volume),
+			fmt.Sprintf("Shuffle: %s · Repeat: %s", shuffle, p.repeat),
+		)
+	}
+
+	if !m.isPremium() {
+		lines = append(lines, "", theme.MutedStyle.Render("Playback control needs Spotify Premium."))
+	} else if m.playbackErr != nil {
+		lines = append(lines, "", "Playback failed: "+m.playbackErr.Error())
+	}
+
+	return title + theme.TableContainerStyle.Render(strings.Join(lines, "\n")) + "\n" + help
+}
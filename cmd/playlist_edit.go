@@ -0,0 +1,420 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var errPlaylistChanged = errors.New("the playlist was changed elsewhere, reload it and try again")
+
+// incompleteRemoveError is returned by a remove that took out every copy of
+// the track but could not put all of the others back.
+type incompleteRemoveError struct {
+	restored, others int
+	err              error
+}
+
+func (e incompleteRemoveError) Error() string {
+	return fmt.Sprintf("removed every copy of the track but put back only %d of the other %d: %v", e.restored, e.others, e.err)
+}
+
+func (e incompleteRemoveError) Unwrap() error {
+	return e.err
+}
+
+// Kinds of playlist edit
+const (
+	editAdd = iota
+	editRemove
+	editMove
+)
+
+// playlistEdit is one change to a playlist's tracks, kept to be undone. Each
+// edit adds, removes or moves a single copy of a track.
+type playlistEdit struct {
+	playlist  Playlist
+	kind      int
+	song      Song
+	from      int   // Position of the track before a remove or move; -1 is its last copy
+	to        int   // Position of the track after an add or move; -1 appends
+	positions []int // Every position of the track before a remove, ascending
+}
+
+// inverse returns the edit that undoes e.
+func (e playlistEdit) inverse() playlistEdit {
+	switch e.kind {
+	case editAdd:
+		e.kind, e.from = editRemove, e.to
+	case editRemove:
+		e.kind, e.to, e.positions = editAdd, e.from, nil
+	case editMove:
+		e.from, e.to = e.to, e.from
+	}
+	return e
+}
+
+// describe summarises the edit for a toast.
+func (e playlistEdit) describe() string {
+	switch e.kind {
+	case editAdd:
+		return "Added " + e.song.Name + " to " + e.playlist.Name
+	case editRemove:
+		return "Removed " + e.song.Name + " from " + e.playlist.Name
+	}
+	return fmt.Sprintf("Moved %s to #%d", e.song.Name, e.to+1)
+}
+
+// playlistRequest is one request to the playlist items endpoint.
+type playlistRequest struct {
+	method  string
+	payload map[string]interface{}
+}
+
+// requests returns the requests that make the edit, relative to the given
+// snapshot. Spotify can only remove a track by removing every copy of it, so a
+// remove adds the other copies back afterwards, each at its position once the
+// removed copy is gone.
+func (e playlistEdit) requests(snapshot string) []playlistRequest {
+	switch e.kind {
+	case editAdd:
+		payload := map[string]interface{}{"uris": []string{e.song.URI}}
+		if e.to >= 0 {
+			payload["position"] = e.to
+		}
+		return []playlistRequest{{"POST", payload}}
+	case editRemove:
+		requests := []playlistRequest{{"DELETE", map[string]interface{}{
+			"tracks":      []map[string]string{{"uri": e.song.URI}},
+			"snapshot_id": snapshot,
+		}}}
+		for _, position := range e.positions {
+			if position == e.from {
+				continue
+			}
+			if position > e.from {
+				position--
+			}
+			requests = append(requests, playlistRequest{"POST", map[string]interface{}{
+				"uris":     []string{e.song.URI},
+				"position": position,
+			}})
+		}
+		return requests
+	}
+
+	// Tracks are inserted before a position counted before the move
+	insertBefore := e.to
+	if e.to > e.from {
+		insertBefore = e.to + 1
+	}
+	return []playlistRequest{{"PUT", map[string]interface{}{
+		"range_start":   e.from,
+		"insert_before": insertBefore,
+		"snapshot_id":   snapshot,
+	}}}
+}
+
+// apply sends the edit to the playlist items endpoint, relative to the given
+// snapshot, and returns the playlist's new snapshot. If a remove fails to put
+// back the other copies of the track, the snapshot it left the playlist at is
+// returned with an incompleteRemoveError.
+func (e playlistEdit) apply(token, snapshot string) (string, error) {
+	requests := e.requests(snapshot)
+	for i, request := range requests {
+		body, err := doAPIRequest(token, request.method, "https://api.spotify.com/v1/playlists/"+e.playlist.ID+"/tracks", request.payload)
+		if err == nil {
+			var response map[string]interface{}
+			if response, err = parseJSONObject(body); err == nil {
+				snapshot, _ = response["snapshot_id"].(string)
+				continue
+			}
+		}
+		if i == 0 {
+			return "", err
+		}
+		return snapshot, incompleteRemoveError{restored: i - 1, others: len(requests) - 1, err: err}
+	}
+	return snapshot, nil
+}
+
+// fetchTrackPositions returns every position of a track in a playlist.
+func fetchTrackPositions(token, id, uri string) ([]int, error) {
+	uris, err := fetchPlaylistTrackURIs(token, id)
+	if err != nil {
+		return nil, err
+	}
+	var positions []int
+	for position, u := range uris {
+		if u == uri {
+			positions = append(positions, position)
+		}
+	}
+	return positions, nil
+}
+
+// fetchPlaylistSnapshot fetches the current snapshot ID of a playlist.
+func fetchPlaylistSnapshot(token, id string) (string, error) {
+	response, err := MakeAPIRequest(token, "https://api.spotify.com/v1/playlists/"+id+"?fields=snapshot_id")
+	if err != nil {
+		return "", err
+	}
+	snapshot, _ := response["snapshot_id"].(string)
+	return snapshot, nil
+}
+
+type playlistEditedMsg struct {
+	edit     playlistEdit
+	snapshot string // Snapshot after the edit, or the current one if it was refused
+	undo     bool
+	page     APIResponse // The refetched page of the playlist, if one was asked for
+	err      error
+}
+
+// playlistEditCmd applies an edit to a playlist, refusing it if the playlist
+// has changed since the given snapshot was taken. If pageURL is set, that
+// page of the playlist's tracks is fetched again afterwards.
+func playlistEditCmd(edit playlistEdit, snapshot string, undo bool, pageURL string) tea.Cmd {
+	return func() tea.Msg {
+		token, _ := auth.GetValidAccessToken()
+		current, err := fetchPlaylistSnapshot(token, edit.playlist.ID)
+		if err != nil {
+			return playlistEditedMsg{err: err}
+		}
+		if current != snapshot {
+			return playlistEditedMsg{edit: edit, snapshot: current, err: errPlaylistChanged}
+		}
+
+		// Removing a track removes every copy of it, so find them all to
+		// put the others back
+		if edit.kind == editRemove {
+			positions, err := fetchTrackPositions(token, edit.playlist.ID, edit.song.URI)
+			if err != nil {
+				return playlistEditedMsg{edit: edit, err: err}
+			}
+			if len(positions) == 0 {
+				return playlistEditedMsg{edit: edit, err: errors.New(edit.song.Name + " is no longer in the playlist")}
+			}
+			edit.positions = positions
+			if edit.from < 0 {
+				edit.from = positions[len(positions)-1]
+			}
+		}
+
+		newSnapshot, err := edit.apply(token, current)
+		if err != nil {
+			return playlistEditedMsg{edit: edit, snapshot: newSnapshot, err: err}
+		}
+
+		msg := playlistEditedMsg{edit: edit, snapshot: newSnapshot, undo: undo}
+		if pageURL != "" {
+			msg.page, msg.err = fetchSongsPage(pageURL)
+		}
+		return msg
+	}
+}
+
+// currentPlaylistPage returns the URL of the page of the open playlist.
+func (m appModel) currentPlaylistPage() string {
+	l := m.lists[viewPlaylistTracks]
+	if len(l.pages) == 0 {
+		return ""
+	}
+	return l.pages[len(l.pages)-1]
+}
+
+// editSelectedTrack removes the track under the cursor of the open playlist,
+// or moves it by step.
+func (m *appModel) editSelectedTrack(kind, step int) tea.Cmd {
+	if m.currentView != viewPlaylistTracks {
+		return nil
+	}
+	song, ok := m.selectedSong()
+	if !ok || song.URI == "" {
+		return nil
+	}
+
+	// Rank counts the items without a track too, unlike the rows
+	pageURL := m.currentPlaylistPage()
+	position := song.Rank - 1
+	edit := playlistEdit{playlist: m.playlist, kind: kind, song: song, from: position, to: position + step}
+	if kind == editMove && (edit.to < 0 || edit.to >= m.playlist.TrackCount) {
+		return nil
+	}
+	return playlistEditCmd(edit, m.playlist.SnapshotID, false, pageURL)
+}
+
+// undoPlaylistEdit undoes the last playlist edit.
+func (m *appModel) undoPlaylistEdit() tea.Cmd {
+	if m.lastEdit == nil {
+		return m.showToast("Nothing to undo")
+	}
+	pageURL := ""
+	if m.currentView == viewPlaylistTracks && m.playlist.ID == m.lastEdit.edit.playlist.ID {
+		pageURL = m.currentPlaylistPage()
+	}
+	return playlistEditCmd(m.lastEdit.edit.inverse(), m.lastEdit.snapshot, true, pageURL)
+}
+
+// playlistEdited records an applied edit and shows its result.
+func (m *appModel) playlistEdited(msg playlistEditedMsg) tea.Cmd {
+	if errors.Is(msg.err, errPlaylistChanged) {
+		m.lastEdit = nil
+		if m.playlist.ID != msg.edit.playlist.ID {
+			return m.showToast(msg.err.Error())
+		}
+
+		// Reload the open playlist, so the next edit applies to what it is now
+		m.playlist.SnapshotID = msg.snapshot
+		toast := m.showToast("The playlist was changed elsewhere and has been reloaded")
+		if m.currentView == viewPlaylistTracks {
+			return tea.Batch(toast, m.openPlaylist(m.playlist))
+		}
+		return toast
+	}
+	var incomplete incompleteRemoveError
+	if errors.As(msg.err, &incomplete) {
+		// The playlist is left neither as it was nor as asked, so there is
+		// nothing sound to undo; reload it to show what it is now
+		m.lastEdit = nil
+		toast := m.showToast("Could not edit playlist: " + msg.err.Error())
+		if m.playlist.ID != msg.edit.playlist.ID {
+			return toast
+		}
+		m.playlist.SnapshotID = msg.snapshot
+		m.playlist.TrackCount -= len(msg.edit.positions) - incomplete.restored
+		if m.currentView == viewPlaylistTracks {
+			return tea.Batch(toast, m.openPlaylist(m.playlist))
+		}
+		return toast
+	}
+	if msg.err != nil && msg.snapshot == "" {
+		return m.showToast("Could not edit playlist: " + msg.err.Error())
+	}
+
+	e := msg.edit
+	if msg.undo {
+		m.lastEdit = nil
+	} else {
+		m.lastEdit = &appliedEdit{e, msg.snapshot}
+	}
+
+	if m.playlist.ID == e.playlist.ID {
+		m.playlist.SnapshotID = msg.snapshot
+		switch e.kind {
+		case editAdd:
+			m.playlist.TrackCount++
+		case editRemove:
+			m.playlist.TrackCount--
+		}
+
+		// Keep the cursor on a moved track, or where a removed one was
+		if msg.err == nil && msg.page.Songs != nil && m.currentView == viewPlaylistTracks {
+			m.replacePage(viewPlaylistTracks, msg.page)
+			l := m.lists[viewPlaylistTracks]
+			switch e.kind {
+			case editMove:
+				l.list.selectItem(&l.table, songAtPosition(msg.page.Songs, e.to))
+			case editRemove:
+				l.list.selectItem(&l.table, songAtPosition(msg.page.Songs, e.from))
+			}
+		}
+	}
+
+	toast := e.describe()
+	if msg.undo {
+		toast = "Undone: " + e.inverse().describe()
+	}
+	return m.showToast(toast)
+}
+
+// songAtPosition returns the index of the first of songs at or after a
+// position in their playlist, or of the last song if there is none.
+func songAtPosition(songs []Song, position int) int {
+	for i, song := range songs {
+		if song.Rank-1 >= position {
+			return i
+		}
+	}
+	return len(songs) - 1
+}
+
+// appliedEdit is the last edit made, with the snapshot it left the playlist
+// at, which undoing it is checked against.
+type appliedEdit struct {
+	edit     playlistEdit
+	snapshot string
+}
+
+var playlistPickerSource = listSource{
+	url: myPlaylistsURL + "?limit=50",
+	columns: []table.Column{
+		{Title: "Name", Width: 40},
+		{Title: "Owner", Width: 20},
+		{Title: "Tracks", Width: 8},
+	},
+	ratios: []float64{0.6, 0.25, 0.15},
+	fetch:  fetchEditablePlaylists,
+	rows: func(response APIResponse, offset int) []table.Row {
+		rows := []table.Row{}
+		for _, playlist := range response.Playlists {
+			rows = append(rows, table.Row{playlist.Name, playlist.Owner, fmt.Sprintf("%d", playlist.TrackCount)})
+		}
+		return rows
+	},
+	footer: theme.HelpStyle.Render("[↑/↓] Navigate  [/] Filter  [enter] Add Track  [q] Cancel"),
+}
+
+// fetchEditablePlaylists fetches every playlist the user can add tracks to:
+// their own and collaborative ones.
+func fetchEditablePlaylists(url string) (APIResponse, error) {
+	me, err := fetchMe()
+	if err != nil {
+		return APIResponse{}, err
+	}
+	playlists, err := collectPlaylists(url)
+	if err != nil {
+		return APIResponse{}, err
+	}
+
+	var editable []Playlist
+	for _, playlist := range playlists {
+		if playlist.OwnerID == me.ID || playlist.Collaborative {
+			editable = append(editable, playlist)
+		}
+	}
+	return APIResponse{Playlists: editable}, nil
+}
+
+// pickPlaylist opens the playlist picker to add the selected track to.
+func (m *appModel) pickPlaylist() tea.Cmd {
+	song, ok := m.selectedSong()
+	if !ok || song.URI == "" || m.currentView == viewPlaylistPicker {
+		return nil
+	}
+	m.pickSong = song
+	m.lists[viewPlaylistPicker].title = "Add " + song.Name + " to Playlist"
+	return m.loadView(viewPlaylistPicker)
+}
+
+// addToPickedPlaylist adds the track being picked for to the playlist under
+// the cursor, at its end, and closes the picker.
+func (m *appModel) addToPickedPlaylist() tea.Cmd {
+	playlists, i := m.lists[viewPlaylistPicker].response.Playlists, m.selectedIndex(viewPlaylistPicker)
+	if i < 0 || i >= len(playlists) {
+		return nil
+	}
+	playlist := playlists[i]
+	m.pop()
+
+	edit := playlistEdit{playlist: playlist, kind: editAdd, song: m.pickSong, to: -1}
+	pageURL := ""
+	if m.currentView == viewPlaylistTracks && m.playlist.ID == playlist.ID {
+		pageURL = m.currentPlaylistPage()
+	}
+	return playlistEditCmd(edit, playlist.SnapshotID, false, pageURL)
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testURI = "spotify:track:test"
+
+func TestPlaylistEditRequests(t *testing.T) {
+	song := Song{Name: "Test", URI: testURI}
+	move := func(from, to int) playlistEdit {
+		return playlistEdit{kind: editMove, song: song, from: from, to: to}
+	}
+	moveRequest := func(rangeStart, insertBefore int) []playlistRequest {
+		return []playlistRequest{{"PUT", map[string]interface{}{
+			"range_start":   rangeStart,
+			"insert_before": insertBefore,
+			"snapshot_id":   "snap",
+		}}}
+	}
+	add := func(position int) playlistRequest {
+		return playlistRequest{"POST", map[string]interface{}{"uris": []string{testURI}, "position": position}}
+	}
+	remove := playlistRequest{"DELETE", map[string]interface{}{
+		"tracks":      []map[string]string{{"uri": testURI}},
+		"snapshot_id": "snap",
+	}}
+
+	tests := []struct {
+		name string
+		edit playlistEdit
+		want []playlistRequest
+	}{
+		{"move up", move(5, 4), moveRequest(5, 4)},
+		{"move down", move(4, 5), moveRequest(4, 6)},
+		{"move to first", move(3, 0), moveRequest(3, 0)},
+		{"move to last", move(8, 9), moveRequest(8, 10)},
+		{"undo move up", move(5, 4).inverse(), moveRequest(4, 6)},
+		{"undo move down", move(4, 5).inverse(), moveRequest(5, 4)},
+		{
+			"add at position",
+			playlistEdit{kind: editAdd, song: song, to: 2},
+			[]playlistRequest{add(2)},
+		},
+		{
+			"add at end",
+			playlistEdit{kind: editAdd, song: song, to: -1},
+			[]playlistRequest{{"POST", map[string]interface{}{"uris": []string{testURI}}}},
+		},
+		{
+			"remove only copy",
+			playlistEdit{kind: editRemove, song: song, from: 3, positions: []int{3}},
+			[]playlistRequest{remove},
+		},
+		{
+			"remove middle of several copies",
+			playlistEdit{kind: editRemove, song: song, from: 4, positions: []int{1, 4, 7}},
+			[]playlistRequest{remove, add(1), add(6)},
+		},
+		{
+			"remove first of several copies",
+			playlistEdit{kind: editRemove, song: song, from: 1, positions: []int{1, 4, 7}},
+			[]playlistRequest{remove, add(3), add(6)},
+		},
+		{
+			"remove last of several copies",
+			playlistEdit{kind: editRemove, song: song, from: 7, positions: []int{1, 4, 7}},
+			[]playlistRequest{remove, add(1), add(4)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.edit.requests("snap"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("requests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaylistEditInverse(t *testing.T) {
+	tests := []struct {
+		name string
+		edit playlistEdit
+		want playlistEdit
+	}{
+		{
+			"move",
+			playlistEdit{kind: editMove, from: 2, to: 5},
+			playlistEdit{kind: editMove, from: 5, to: 2},
+		},
+		{
+			"add at position",
+			playlistEdit{kind: editAdd, from: 0, to: 3},
+			playlistEdit{kind: editRemove, from: 3, to: 3},
+		},
+		{
+			"add at end removes the last copy",
+			playlistEdit{kind: editAdd, to: -1},
+			playlistEdit{kind: editRemove, from: -1, to: -1},
+		},
+		{
+			"remove of several copies adds back only the removed one",
+			playlistEdit{kind: editRemove, from: 4, positions: []int{1, 4, 7}},
+			playlistEdit{kind: editAdd, from: 4, to: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.edit.inverse(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("inverse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPlaylistEditRoundTrip applies an edit and its inverse to a playlist
+// held in memory, the way Spotify applies the requests, and checks that the
+// playlist ends up as it was.
+func TestPlaylistEditRoundTrip(t *testing.T) {
+	start := []string{"a", testURI, "b", "c", testURI, "d", testURI}
+
+	tests := []struct {
+		name string
+		edit playlistEdit
+		want []string
+	}{
+		{"move up", playlistEdit{kind: editMove, from: 3, to: 2}, []string{"a", testURI, "c", "b", testURI, "d", testURI}},
+		{"move down", playlistEdit{kind: editMove, from: 2, to: 3}, []string{"a", testURI, "c", "b", testURI, "d", testURI}},
+		{"move to first", playlistEdit{kind: editMove, from: 5, to: 0}, []string{"d", "a", testURI, "b", "c", testURI, testURI}},
+		{"move to last", playlistEdit{kind: editMove, from: 0, to: 6}, []string{testURI, "b", "c", testURI, "d", testURI, "a"}},
+		{"add", playlistEdit{kind: editAdd, to: 2}, []string{"a", testURI, testURI, "b", "c", testURI, "d", testURI}},
+		{"add at end", playlistEdit{kind: editAdd, to: -1}, append(append([]string{}, start...), testURI)},
+		{"remove one of several copies", playlistEdit{kind: editRemove, from: 4}, []string{"a", testURI, "b", "c", "d", testURI}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.edit.song = Song{URI: testURI}
+			edited := applyRequests(t, start, tt.edit.withPositions(start))
+			if !reflect.DeepEqual(edited, tt.want) {
+				t.Fatalf("edit gave %v, want %v", edited, tt.want)
+			}
+
+			undo := tt.edit.withPositions(start).inverse()
+			if undo.kind == editRemove && undo.from < 0 {
+				undo.from = len(edited) - 1
+			}
+			if undone := applyRequests(t, edited, undo.withPositions(edited)); !reflect.DeepEqual(undone, start) {
+				t.Errorf("undo gave %v, want %v", undone, start)
+			}
+		})
+	}
+}
+
+// withPositions sets the positions of the edit's track in tracks, as
+// playlistEditCmd does before a remove.
+func (e playlistEdit) withPositions(tracks []string) playlistEdit {
+	if e.kind != editRemove {
+		return e
+	}
+	e.positions = nil
+	for i, uri := range tracks {
+		if uri == e.song.URI {
+			e.positions = append(e.positions, i)
+		}
+	}
+	return e
+}
+
+// applyRequests applies the requests of an edit to a copy of tracks.
+func applyRequests(t *testing.T, tracks []string, e playlistEdit) []string {
+	t.Helper()
+	tracks = append([]string{}, tracks...)
+	for _, r := range e.requests("snap") {
+		switch r.method {
+		case "POST":
+			uri := r.payload["uris"].([]string)[0]
+			position, ok := r.payload["position"].(int)
+			if !ok {
+				position = len(tracks)
+			}
+			tracks = append(tracks[:position], append([]string{uri}, tracks[position:]...)...)
+		case "DELETE":
+			uri := r.payload["tracks"].([]map[string]string)[0]["uri"]
+			kept := tracks[:0]
+			for _, track := range tracks {
+				if track != uri {
+					kept = append(kept, track)
+				}
+			}
+			tracks = kept
+		case "PUT":
+			start, before := r.payload["range_start"].(int), r.payload["insert_before"].(int)
+			moved := tracks[start]
+			rest := append(append([]string{}, tracks[:start]...), tracks[start+1:]...)
+			if before > start {
+				before--
+			}
+			tracks = append(rest[:before], append([]string{moved}, rest[before:]...)...)
+		default:
+			t.Fatalf("unexpected method %s", r.method)
+		}
+	}
+	return tracks
+}
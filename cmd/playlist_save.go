@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	"github.com/bytegrunt/go-spotify-me/internal/logging"
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// playlistItemsBatchSize is the most tracks the playlist items endpoints
+// accept at once.
+const playlistItemsBatchSize = 100
+
+func playlistCommand() command {
+	return command{
+		name:  "playlist",
+		usage: "playlist [--name NAME] [--range short|medium|long] [--limit N] [--public]",
+		flags: func(fs *flag.FlagSet) func(args []string) error {
+			name := fs.String("name", "", "playlist to create or overwrite (default: Top Tracks · <range>)")
+			rangeName := fs.String("range", "short", "time range of the top tracks")
+			limit := fs.Int("limit", 50, "number of top tracks to add, 0 for all")
+			public := fs.Bool("public", false, "make a newly created playlist public")
+
+			return func(args []string) error {
+				timeRange, err := parseTimeRange(*rangeName)
+				if err != nil {
+					return err
+				}
+				if *limit < 0 {
+					return fmt.Errorf("invalid limit %d", *limit)
+				}
+				if *name == "" {
+					*name = defaultPlaylistName(timeRange)
+				}
+				return runPlaylist(*name, timeRange, *limit, *public)
+			}
+		},
+		complete: func(flag string) []string {
+			if flag == "range" {
+				return timeRangeNames()
+			}
+			return nil
+		},
+	}
+}
+
+// defaultPlaylistName names a playlist of top tracks after their time range.
+func defaultPlaylistName(timeRange string) string {
+	return "Top Tracks · " + timeRangeLabel(timeRange)
+}
+
+func runPlaylist(name, timeRange string, limit int, public bool) error {
+	clientID, err := GetClientID()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve client ID: %w", err)
+	}
+	if clientID == "" {
+		return fmt.Errorf("no client ID configured, run the TUI once or set SPOTIFY_CLIENT_ID")
+	}
+	if err := Login(); err != nil {
+		return fmt.Errorf("failed to log in: %w", err)
+	}
+
+	songs, err := fetchAllSongs(timeRange)
+	if err != nil {
+		return fmt.Errorf("failed to fetch top tracks: %w", err)
+	}
+	if limit > 0 && len(songs) > limit {
+		songs = songs[:limit]
+	}
+
+	_, replaced, err := savePlaylist(name, public, true, songs)
+	if err != nil {
+		return err
+	}
+
+	if replaced {
+		fmt.Printf("Replaced the tracks of %q with %d top tracks\n", name, len(songs))
+	} else {
+		fmt.Printf("Created %q with %d top tracks\n", name, len(songs))
+	}
+	return nil
+}
+
+// fetchOwnedPlaylists fetches the playlists the user owns, by name.
+func fetchOwnedPlaylists() (Me, map[string]Playlist, error) {
+	me, err := fetchMe()
+	if err != nil {
+		return Me{}, nil, fmt.Errorf("failed to fetch profile: %w", err)
+	}
+	playlists, err := collectPlaylists(myPlaylistsURL + "?limit=50")
+	if err != nil {
+		return Me{}, nil, fmt.Errorf("failed to fetch playlists: %w", err)
+	}
+
+	owned := make(map[string]Playlist)
+	for _, p := range playlists {
+		if _, ok := owned[p.Name]; !ok && p.OwnerID == me.ID {
+			owned[p.Name] = p
+		}
+	}
+	return me, owned, nil
+}
+
+// savePlaylist fills a new playlist of the given name with songs. With
+// overwrite, the user's playlist of that name, if there is one, has its tracks
+// replaced instead. It reports whether an existing playlist was replaced.
+func savePlaylist(name string, public, overwrite bool, songs []Song) (Playlist, bool, error) {
+	var uris []string
+	for _, song := range songs {
+		if song.URI != "" {
+			uris = append(uris, song.URI)
+		}
+	}
+	if len(uris) == 0 {
+		return Playlist{}, false, fmt.Errorf("no tracks to save to %q", name)
+	}
+
+	me, owned, err := fetchOwnedPlaylists()
+	if err != nil {
+		return Playlist{}, false, err
+	}
+	playlist, replaced := owned[name]
+	replaced = replaced && overwrite
+
+	token, _ := auth.GetValidAccessToken()
+	if !replaced {
+		payload := map[string]interface{}{
+			"name":        name,
+			"public":      public,
+			"description": "Created by " + appName,
+		}
+		body, err := doAPIRequest(token, "POST", "https://api.spotify.com/v1/users/"+me.ID+"/playlists", payload)
+		if err != nil {
+			return Playlist{}, false, fmt.Errorf("failed to create playlist: %w", err)
+		}
+		response, err := parseJSONObject(body)
+		if err != nil {
+			return Playlist{}, false, err
+		}
+		playlist = parsePlaylist(response)
+	}
+
+	// The first batch replaces the playlist's tracks, the rest are appended
+	itemsURL := "https://api.spotify.com/v1/playlists/" + playlist.ID + "/tracks"
+	method := "PUT"
+	for start := 0; start < len(uris); start += playlistItemsBatchSize {
+		batch := uris[start:min(start+playlistItemsBatchSize, len(uris))]
+		if _, err := doAPIRequest(token, method, itemsURL, map[string]interface{}{"uris": batch}); err != nil {
+			return Playlist{}, false, fmt.Errorf("failed to add tracks to playlist: %w", err)
+		}
+		method = "POST"
+	}
+
+	playlist.TrackCount = len(uris)
+	return playlist, replaced, nil
+}
+
+// playlistPrompt asks for the name of a playlist to save songs to, in the
+// status line. Saving under the name of a playlist the user owns overwrites
+// it, but only once that has been confirmed; until the user's playlists have
+// been fetched, a new playlist is created.
+type playlistPrompt struct {
+	input     textinput.Model
+	songs     []Song              // Songs to save; nil when the prompt is closed
+	owned     map[string]Playlist // The user's playlists by name, once fetched
+	confirmed string              // Name whose overwrite enter has to confirm
+}
+
+type ownedPlaylistsMsg struct {
+	playlists map[string]Playlist
+}
+
+func fetchOwnedPlaylistsCmd() tea.Cmd {
+	return func() tea.Msg {
+		_, owned, err := fetchOwnedPlaylists()
+		if err != nil {
+			logging.DebugLog("Failed to fetch owned playlists: %v", err)
+		}
+		return ownedPlaylistsMsg{owned}
+	}
+}
+
+type playlistSavedMsg struct {
+	playlist Playlist
+	replaced bool
+	err      error
+}
+
+func savePlaylistCmd(name string, overwrite bool, songs []Song) tea.Cmd {
+	return func() tea.Msg {
+		playlist, replaced, err := savePlaylist(name, false, overwrite, songs)
+		return playlistSavedMsg{playlist, replaced, err}
+	}
+}
+
+// open asks for a playlist name to save songs to, suggesting name.
+func (p *playlistPrompt) open(name string, songs []Song) tea.Cmd {
+	p.input = textinput.New()
+	p.input.Prompt = "Save as playlist: "
+	p.input.CharLimit = 100
+	p.input.Width = 40
+	p.input.SetValue(name)
+	p.input.Focus()
+	p.songs = songs
+	p.owned, p.confirmed = nil, ""
+	return tea.Batch(textinput.Blink, fetchOwnedPlaylistsCmd())
+}
+
+func (p playlistPrompt) active() bool {
+	return p.songs != nil
+}
+
+// update handles keys while the prompt is open.
+func (p *playlistPrompt) update(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		p.songs = nil
+		return nil
+	case "enter":
+		name, songs := p.input.Value(), p.songs
+		if name == "" {
+			return nil
+		}
+		_, overwrite := p.owned[name]
+		if overwrite && p.confirmed != name {
+			p.confirmed = name
+			return nil
+		}
+		p.songs = nil
+		return savePlaylistCmd(name, overwrite, songs)
+	}
+
+	p.confirmed = ""
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return cmd
+}
+
+func (p playlistPrompt) view() string {
+	hint := fmt.Sprintf("  %d tracks  [enter] Save  [esc] Cancel", len(p.songs))
+	if existing, ok := p.owned[p.input.Value()]; ok {
+		hint = fmt.Sprintf("  %d tracks, will overwrite %s (%d tracks)  [enter] Overwrite  [esc] Cancel", len(p.songs), existing.Name, existing.TrackCount)
+		if p.confirmed == existing.Name {
+			hint = fmt.Sprintf("  Overwrite %s (%d tracks)? [enter] Confirm  [esc] Cancel", existing.Name, existing.TrackCount)
+		}
+	}
+	return "  " + p.input.View() + theme.MutedStyle.Render(hint)
+}
+
+// saveShownSongs asks for a playlist name to save the top songs to, as they
+// are currently filtered and sorted.
+func (m *appModel) saveShownSongs() tea.Cmd {
+	var songs []Song
+	for row := range m.songList.order {
+		if i := m.songList.index(row); i >= 0 && i < len(m.songs.Songs) {
+			songs = append(songs, m.songs.Songs[i])
+		}
+	}
+	if len(songs) == 0 {
+		return nil
+	}
+	return m.prompt.open(defaultPlaylistName(m.timeRange), songs)
+}
+
+// playlistSaved confirms a playlist was saved.
+func (m *appModel) playlistSaved(msg playlistSavedMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.showToast("Could not save playlist: " + msg.err.Error())
+	}
+	if msg.replaced {
+		return m.showToast(fmt.Sprintf("Replaced the tracks of %s with %d tracks", msg.playlist.Name, msg.playlist.TrackCount))
+	}
+	return m.showToast(fmt.Sprintf("Created %s with %d tracks", msg.playlist.Name, msg.playlist.TrackCount))
+}
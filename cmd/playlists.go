@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Playlist represents one of the user's playlists
+type Playlist struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Owner         string `json:"owner"`
+	OwnerID       string `json:"owner_id"`
+	TrackCount    int    `json:"track_count"`
+	Public        bool   `json:"public"`
+	Collaborative bool   `json:"collaborative"`
+	SnapshotID    string `json:"snapshot_id"`
+	URL           string `json:"url"`
+}
+
+const myPlaylistsURL = "https://api.spotify.com/v1/me/playlists"
+
+// playlistTracksURL returns the URL of the first page of a playlist's tracks.
+func playlistTracksURL(id string) string {
+	return "https://api.spotify.com/v1/playlists/" + id + "/tracks?limit=50"
+}
+
+var playlistsSource = listSource{
+	url: myPlaylistsURL + "?limit=50",
+	columns: []table.Column{
+		{Title: "#", Width: 4},
+		{Title: "Name", Width: 40},
+		{Title: "Owner", Width: 20},
+		{Title: "Tracks", Width: 8},
+		{Title: "Public", Width: 6},
+		{Title: "Collaborative", Width: 6},
+	},
+	ratios: []float64{0.05, 0.4, 0.2, 0.1, 0.1, 0.15},
+	fetch:  fetchPlaylistsPage,
+	rows: func(response APIResponse, offset int) []table.Row {
+		rows := []table.Row{}
+		for i, playlist := range response.Playlists {
+			rows = append(rows, table.Row{
+				fmt.Sprintf("%d", offset+i+1),
+				playlist.Name,
+				playlist.Owner,
+				fmt.Sprintf("%d", playlist.TrackCount),
+				yesNo(playlist.Public),
+				yesNo(playlist.Collaborative),
+			})
+		}
+		return rows
+	},
+}
+
+// playlistTracksSource lists the tracks of the playlist opened last, whose
+// URL is set by openPlaylist.
+var playlistTracksSource = listSource{
+	columns: []table.Column{
+		{Title: "#", Width: 4},
+		{Title: "Name", Width: 40},
+		{Title: "Artist", Width: 20},
+		{Title: "Album", Width: 30},
+		{Title: "Added", Width: 16},
+	},
+	ratios:   []float64{0.05, 0.33, 0.2, 0.24, 0.18},
+	fetch:    fetchSongsPage,
+	rows:     likedSource.rows,
+	sortKeys: likedSource.sortKeys,
+	footer:   theme.HelpStyle.Render("[↑/↓] Navigate  [←/→] Page  [/] Filter  [x] Remove  [J/K] Move Down/Up  [z] Undo  [enter] Details  [q] Back"),
+}
+
+// openPlaylist shows the tracks of a playlist.
+func (m *appModel) openPlaylist(playlist Playlist) tea.Cmd {
+	m.playlist = playlist
+	m.lists[viewPlaylistTracks].title = playlist.Name
+	return fetchPageCmd(viewPlaylistTracks, playlistTracksURL(playlist.ID), 0, m.currentView)
+}
+
+// openSelectedPlaylist opens the playlist under the cursor of the Playlists view.
+func (m *appModel) openSelectedPlaylist() tea.Cmd {
+	playlists, i := m.lists[viewPlaylists].response.Playlists, m.selectedIndex(viewPlaylists)
+	if i < 0 || i >= len(playlists) {
+		return nil
+	}
+	return m.openPlaylist(playlists[i])
+}
+
+func fetchPlaylistsPage(url string) (APIResponse, error) {
+	token, _ := auth.GetValidAccessToken()
+	response, err := MakeAPIRequest(token, url)
+	if err != nil {
+		return APIResponse{}, err
+	}
+
+	playlists := parsePlaylists(response)
+	next, _ := response["next"].(string)
+	prev, _ := response["previous"].(string)
+
+	return APIResponse{
+		Playlists: playlists,
+		Next:      next,
+		Prev:      prev,
+	}, nil
+}
+
+// collectPlaylists follows the next links from url and returns the playlists
+// of every page, in order. If there are more than maxPages pages, the
+// playlists of the first ones are returned with errPageLimit.
+func collectPlaylists(url string) ([]Playlist, error) {
+	var playlists []Playlist
+	for page := 0; url != ""; page++ {
+		if page == maxPages {
+			return playlists, errPageLimit
+		}
+		response, err := fetchPlaylistsPage(url)
+		if err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, response.Playlists...)
+		url = response.Next
+	}
+	return playlists, nil
+}
+
+// fetchPlaylistTrackURIs returns the URI of every item of a playlist, in
+// order, with "" for an item that no longer has a track. If there are more than
+// maxPages pages, the URIs of the first ones are returned with errPageLimit.
+func fetchPlaylistTrackURIs(token, id string) ([]string, error) {
+	var uris []string
+	url := "https://api.spotify.com/v1/playlists/" + id + "/tracks?fields=next,items(track(uri))&limit=100"
+	for page := 0; url != ""; page++ {
+		if page == maxPages {
+			return uris, errPageLimit
+		}
+		response, err := MakeAPIRequest(token, url)
+		if err != nil {
+			return nil, err
+		}
+		items, _ := response["items"].([]interface{})
+		for _, item := range items {
+			uri := ""
+			if item, ok := item.(map[string]interface{}); ok {
+				if track, ok := item["track"].(map[string]interface{}); ok {
+					uri, _ = track["uri"].(string)
+				}
+			}
+			uris = append(uris, uri)
+		}
+		url, _ = response["next"].(string)
+	}
+	return uris, nil
+}
+
+func parsePlaylists(response map[string]interface{}) []Playlist {
+	items, ok := response["items"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var playlists []Playlist
+	for _, item := range items {
+		playlist, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		playlists = append(playlists, parsePlaylist(playlist))
+	}
+
+	return playlists
+}
+
+func parsePlaylist(playlist map[string]interface{}) Playlist {
+	id, _ := playlist["id"].(string)
+	name, _ := playlist["name"].(string)
+	public, _ := playlist["public"].(bool)
+	collaborative, _ := playlist["collaborative"].(bool)
+	snapshotID, _ := playlist["snapshot_id"].(string)
+
+	owner, ownerID := "", ""
+	if ownerObject, ok := playlist["owner"].(map[string]interface{}); ok {
+		owner, _ = ownerObject["display_name"].(string)
+		ownerID, _ = ownerObject["id"].(string)
+		if owner == "" {
+			owner = ownerID
+		}
+	}
+
+	trackCount := 0.0
+	if tracks, ok := playlist["tracks"].(map[string]interface{}); ok {
+		trackCount, _ = tracks["total"].(float64)
+	}
+
+	url := ""
+	if externalURLs, ok := playlist["external_urls"].(map[string]interface{}); ok {
+		url, _ = externalURLs["spotify"].(string)
+	}
+
+	return Playlist{
+		ID:            id,
+		Name:          name,
+		Owner:         owner,
+		OwnerID:       ownerID,
+		TrackCount:    int(trackCount),
+		Public:        public,
+		Collaborative: collaborative,
+		SnapshotID:    snapshotID,
+		URL:           url,
+	}
+}
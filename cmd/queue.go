@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const queueURL = "https://api.spotify.com/v1/me/player/queue"
+
+var queueSource = listSource{
+	url: queueURL,
+	columns: []table.Column{
+		{Title: "#", Width: 4},
+		{Title: "Name", Width: 40},
+		{Title: "Artist", Width: 20},
+		{Title: "Album", Width: 30},
+		{Title: "Duration", Width: 8},
+	},
+	ratios: []float64{0.05, 0.35, 0.22, 0.28, 0.1},
+	fetch:  fetchQueue,
+	rows: func(response APIResponse, offset int) []table.Row {
+		rows := []table.Row{}
+		for i, song := range response.Songs {
+			rows = append(rows, table.Row{
+				fmt.Sprintf("%d", offset+i+1),
+				song.Name,
+				song.Artist,
+				song.Album,
+				formatDuration(song.DurationMs),
+			})
+		}
+		return rows
+	},
+	footer: theme.HelpStyle.Render("[↑/↓] Navigate  [/] Filter  [enter] Details  [q] Back  [H] Home"),
+}
+
+// fetchQueue fetches the tracks coming up after the current one. The queue
+// comes as a single page.
+func fetchQueue(url string) (APIResponse, error) {
+	token, _ := auth.GetValidAccessToken()
+	response, err := MakeAPIRequest(token, url)
+	if err != nil {
+		return APIResponse{}, err
+	}
+
+	var songs []Song
+	if items, ok := response["queue"].([]interface{}); ok {
+		songs = parseSongItems(items, 0)
+	}
+	return APIResponse{Songs: songs}, nil
+}
+
+type queuedMsg struct {
+	song Song
+	err  error
+}
+
+// addToQueueCmd adds a track to the end of the queue.
+func addToQueueCmd(song Song) tea.Cmd {
+	return func() tea.Msg {
+		token, _ := auth.GetValidAccessToken()
+		_, err := doAPIRequest(token, "POST", queueURL+"?uri="+url.QueryEscape(song.URI), nil)
+		return queuedMsg{song, err}
+	}
+}
+
+// queueSelected adds the track under the cursor to the queue.
+func (m *appModel) queueSelected() tea.Cmd {
+	song, ok := m.selectedSong()
+	if !ok || song.URI == "" {
+		return nil
+	}
+	if !m.isPremium() {
+		return m.showToast(errNotPremium.Error())
+	}
+	return addToQueueCmd(song)
+}
+
+// queued confirms a track was queued, refreshing the queue view if it is open.
+func (m *appModel) queued(msg queuedMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.showToast("Could not queue " + msg.song.Name + ": " + msg.err.Error())
+	}
+
+	toast := m.showToast("Added " + msg.song.Name + " to the queue")
+	if m.currentView == viewQueue {
+		return tea.Batch(toast, m.loadView(viewQueue))
+	}
+	return toast
+}
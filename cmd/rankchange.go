@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytegrunt/go-spotify-me/internal/logging"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rangeArtistsMsg carries every top artist for a time range, fetched in the
+// background to compare ranks across ranges.
+type rangeArtistsMsg struct {
+	timeRange string
+	artists   []Artist
+	err       error
+}
+
+// rangeSongsMsg carries every top song for a time range, fetched in the
+// background to compare ranks across ranges.
+type rangeSongsMsg struct {
+	timeRange string
+	songs     []Song
+	err       error
+}
+
+// baselineRange returns the time range the given range is compared against:
+// each range is compared with the next longer one, and the long term, having
+// none, with the medium term.
+func baselineRange(timeRange string) string {
+	switch timeRange {
+	case shortTerm:
+		return mediumTerm
+	case mediumTerm:
+		return longTerm
+	}
+	return mediumTerm
+}
+
+// baselineIsNewer reports whether the time range is compared against a
+// shorter, more recent one, as the long term is. Its items are then not new
+// but old favourites when they are missing from the baseline.
+func baselineIsNewer(timeRange string) bool {
+	return timeRange == longTerm
+}
+
+// fetchRangeArtistsCmds fetches, in the background, every time range of top
+// artists that is neither cached nor already being fetched.
+func (m *appModel) fetchRangeArtistsCmds() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, timeRange := range timeRanges {
+		key := "artists:" + timeRange
+		if _, ok := m.rangeArtists[timeRange]; ok || m.pendingRanges[key] {
+			continue
+		}
+		m.pendingRanges[key] = true
+		delete(m.rangeErrs, key)
+		cmds = append(cmds, func() tea.Msg {
+			artists, err := fetchAllArtists(timeRange)
+			return rangeArtistsMsg{timeRange, artists, err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// fetchRangeSongsCmds fetches, in the background, every time range of top
+// songs that is neither cached nor already being fetched.
+func (m *appModel) fetchRangeSongsCmds() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, timeRange := range timeRanges {
+		key := "songs:" + timeRange
+		if _, ok := m.rangeSongs[timeRange]; ok || m.pendingRanges[key] {
+			continue
+		}
+		m.pendingRanges[key] = true
+		delete(m.rangeErrs, key)
+		cmds = append(cmds, func() tea.Msg {
+			songs, err := fetchAllSongs(timeRange)
+			return rangeSongsMsg{timeRange, songs, err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// storeRangeArtists caches the result of a background fetch. A failed fetch
+// is kept for the views that need the range, and is fetched again when they
+// ask for it.
+func (m *appModel) storeRangeArtists(msg rangeArtistsMsg) {
+	delete(m.pendingRanges, "artists:"+msg.timeRange)
+	if msg.err != nil {
+		logging.DebugLog("Failed to fetch top artists for %s: %v", msg.timeRange, msg.err)
+		m.rangeErrs["artists:"+msg.timeRange] = msg.err
+		return
+	}
+	m.rangeArtists[msg.timeRange] = msg.artists
+}
+
+// storeRangeSongs caches the result of a background fetch. A failed fetch is
+// kept as for storeRangeArtists.
+func (m *appModel) storeRangeSongs(msg rangeSongsMsg) {
+	delete(m.pendingRanges, "songs:"+msg.timeRange)
+	if msg.err != nil {
+		logging.DebugLog("Failed to fetch top songs for %s: %v", msg.timeRange, msg.err)
+		m.rangeErrs["songs:"+msg.timeRange] = msg.err
+		return
+	}
+	m.rangeSongs[msg.timeRange] = msg.songs
+}
+
+func artistRanks(artists []Artist) map[string]int {
+	ranks := make(map[string]int, len(artists))
+	for _, artist := range artists {
+		ranks[artist.ID] = artist.Rank
+	}
+	return ranks
+}
+
+func songRanks(songs []Song) map[string]int {
+	ranks := make(map[string]int, len(songs))
+	for _, song := range songs {
+		ranks[song.ID] = song.Rank
+	}
+	return ranks
+}
+
+// rankChange renders how an item's rank moved relative to the baseline ranks:
+// absent if it is missing from the baseline, otherwise up, down or unchanged.
+// A nil baseline means the ranks are not known (yet) and renders nothing.
+func rankChange(id string, rank int, baseline map[string]int, absent string) string {
+	if baseline == nil {
+		return ""
+	}
+	baseRank, ok := baseline[id]
+	switch {
+	case !ok:
+		return absent
+	case baseRank > rank:
+		return fmt.Sprintf("▲%d", baseRank-rank)
+	case baseRank < rank:
+		return fmt.Sprintf("▼%d", rank-baseRank)
+	}
+	return "="
+}
+
+// rankChangeKey returns the change of rank shown by rankChange as a number to
+// sort by: the places climbed, negative when fallen. A new entry counts as
+// having climbed from just below the end of the baseline.
+func rankChangeKey(id string, rank int, baseline map[string]int) string {
+	if baseline == nil {
+		return "0"
+	}
+	baseRank, ok := baseline[id]
+	if !ok {
+		baseRank = len(baseline) + 1
+	}
+	return fmt.Sprintf("%d", baseRank-rank)
+}
+
+// absentLabel is what the rank change of an item missing from the baseline
+// of the current time range shows.
+func (m appModel) absentLabel() string {
+	if baselineIsNewer(m.timeRange) {
+		return "old"
+	}
+	return "new"
+}
+
+// baselineArtistRanks returns the ranks of the cached baseline range of the
+// current time range, or nil if there is none.
+func (m appModel) baselineArtistRanks() map[string]int {
+	artists, ok := m.rangeArtists[baselineRange(m.timeRange)]
+	if !ok {
+		return nil
+	}
+	return artistRanks(artists)
+}
+
+// baselineSongRanks returns the ranks of the cached baseline range of the
+// current time range, or nil if there is none.
+func (m appModel) baselineSongRanks() map[string]int {
+	songs, ok := m.rangeSongs[baselineRange(m.timeRange)]
+	if !ok {
+		return nil
+	}
+	return songRanks(songs)
+}
+
+// droppedArtists returns the names of artists that ranked in the baseline
+// range but do not appear in the current one. Only as many baseline
+// entries are considered as the current range holds, so a shorter list does
+// not count everything below its end as dropped.
+func (m appModel) droppedArtists() []string {
+	current, ok := m.rangeArtists[m.timeRange]
+	baseline, hasBaseline := m.rangeArtists[baselineRange(m.timeRange)]
+	if !ok || !hasBaseline {
+		return nil
+	}
+
+	ranks := artistRanks(current)
+	var dropped []string
+	for i, artist := range baseline {
+		if i >= len(current) {
+			break
+		}
+		if _, ok := ranks[artist.ID]; !ok {
+			dropped = append(dropped, artist.Name)
+		}
+	}
+	return dropped
+}
+
+// droppedSongs returns the names of songs that ranked in the baseline range
+// but no longer appear in the current one, as for droppedArtists.
+func (m appModel) droppedSongs() []string {
+	current, ok := m.rangeSongs[m.timeRange]
+	baseline, hasBaseline := m.rangeSongs[baselineRange(m.timeRange)]
+	if !ok || !hasBaseline {
+		return nil
+	}
+
+	ranks := songRanks(current)
+	var dropped []string
+	for i, song := range baseline {
+		if i >= len(current) {
+			break
+		}
+		if _, ok := ranks[song.ID]; !ok {
+			dropped = append(dropped, song.Name)
+		}
+	}
+	return dropped
+}
+
+// renderDropped summarizes the dropped items below a table.
+func (m appModel) renderDropped(dropped []string) string {
+	if len(dropped) == 0 {
+		return ""
+	}
+
+	const maxShown = 5
+	summary := strings.Join(dropped, ", ")
+	if len(dropped) > maxShown {
+		summary = fmt.Sprintf("%s (+%d more)", strings.Join(dropped[:maxShown], ", "), len(dropped)-maxShown)
+	}
+	if baselineIsNewer(m.timeRange) {
+		return "\n" + fmt.Sprintf("  Rising in %s: %s", timeRangeLabel(baselineRange(m.timeRange)), summary)
+	}
+	return "\n" + fmt.Sprintf("  Dropped since %s: %s", timeRangeLabel(baselineRange(m.timeRange)), summary)
+}
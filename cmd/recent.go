@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/table"
+)
+
+var recentSource = listSource{
+	url: recentlyPlayedURL,
+	columns: []table.Column{
+		{Title: "#", Width: 4},
+		{Title: "Name", Width: 40},
+		{Title: "Artist", Width: 20},
+		{Title: "Album", Width: 30},
+		{Title: "Played", Width: 16},
+	},
+	ratios: []float64{0.05, 0.33, 0.2, 0.24, 0.18},
+	fetch:  fetchRecentPage,
+	rows: func(response APIResponse, offset int) []table.Row {
+		rows := []table.Row{}
+		for i, song := range response.Songs {
+			rows = append(rows, table.Row{
+				fmt.Sprintf("%d", offset+i+1),
+				song.Name,
+				song.Artist,
+				song.Album,
+				formatTimeAgo(song.PlayedAt),
+			})
+		}
+		return rows
+	},
+	sortKeys: func(response APIResponse) map[int][]string {
+		played := make([]string, len(response.Songs))
+		for i, song := range response.Songs {
+			played[i] = timestampKey(song.PlayedAt)
+		}
+		return map[int][]string{4: played}
+	},
+	footer: theme.HelpStyle.Render("[↑/↓] Navigate  [←] Newer  [→] Older  [/] Filter  [o/O] Sort  [enter] Details  [q] Back  [H] Home"),
+}
+
+// fetchRecentPage fetches a page of recently played tracks. The endpoint pages
+// with cursors rather than offsets: the next page holds the plays before the
+// oldest play of this one.
+func fetchRecentPage(url string) (APIResponse, error) {
+	token, _ := auth.GetValidAccessToken()
+	response, err := MakeAPIRequest(token, url)
+	if err != nil {
+		return APIResponse{}, err
+	}
+
+	songs := parseSongs(response)
+	next := ""
+	if cursors, ok := response["cursors"].(map[string]interface{}); ok && len(songs) > 0 {
+		if before, ok := cursors["before"].(string); ok && before != "" {
+			next = recentlyPlayedURL + "&before=" + before
+		}
+	}
+
+	return APIResponse{Songs: songs, Next: next}, nil
+}
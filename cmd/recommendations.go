@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const recommendationsURL = "https://api.spotify.com/v1/recommendations"
+
+// maxSeeds is the most artists and tracks together that recommendations can
+// be seeded with.
+const maxSeeds = 5
+
+// recommendationsDebounce is how long the sliders have to rest before the
+// recommendations are fetched again.
+const recommendationsDebounce = 400 * time.Millisecond
+
+// sliderWidth is the width of the bar of a slider.
+const sliderWidth = 30
+
+// seed is a top artist or track the recommendations are based on.
+type seed struct {
+	artist bool
+	id     string
+	name   string
+}
+
+// slider tunes a target attribute of the recommended tracks. A slider that
+// has not been moved sets no target.
+type slider struct {
+	label    string
+	param    string // Name of the target parameter, without "target_"
+	min, max float64
+	step     float64
+	format   string // Verb to print the value with, then any unit
+	value    float64
+	set      bool
+}
+
+// recommendationsState is the state of the recommendations view, kept between
+// visits.
+type recommendationsState struct {
+	seeds   []seed
+	sliders []slider
+	focus   int // Index of the slider the arrow keys move
+	seq     int // Incremented per fetch; only the latest one is shown
+	loading bool
+	err     error
+}
+
+func newRecommendationsState() recommendationsState {
+	return recommendationsState{
+		sliders: []slider{
+			{label: "Popularity", param: "popularity", max: 100, step: 5, format: "%.0f", value: 50},
+			{label: "Energy", param: "energy", max: 1, step: 0.05, format: "%.2f", value: 0.5},
+			{label: "Tempo", param: "tempo", min: 60, max: 200, step: 5, format: "%.0f BPM", value: 120},
+		},
+	}
+}
+
+// url returns the recommendations URL for the seeds and the moved sliders.
+func (r recommendationsState) url() string {
+	var artists, tracks []string
+	for _, s := range r.seeds {
+		if s.artist {
+			artists = append(artists, s.id)
+		} else {
+			tracks = append(tracks, s.id)
+		}
+	}
+
+	params := url.Values{}
+	params.Set("limit", fmt.Sprintf("%d", listPageSize))
+	if len(artists) > 0 {
+		params.Set("seed_artists", strings.Join(artists, ","))
+	}
+	if len(tracks) > 0 {
+		params.Set("seed_tracks", strings.Join(tracks, ","))
+	}
+	for _, s := range r.sliders {
+		if s.set {
+			params.Set("target_"+s.param, fmt.Sprintf(strings.Fields(s.format)[0], s.value))
+		}
+	}
+	return recommendationsURL + "?" + params.Encode()
+}
+
+var recommendationsSource = listSource{
+	columns: []table.Column{
+		{Title: "#", Width: 4},
+		{Title: "Name", Width: 40},
+		{Title: "Artist", Width: 20},
+		{Title: "Album", Width: 30},
+		{Title: "Popularity", Width: 10},
+	},
+	ratios: []float64{0.05, 0.33, 0.22, 0.28, 0.12},
+	fetch:  fetchRecommendations,
+	rows: func(response APIResponse, offset int) []table.Row {
+		rows := []table.Row{}
+		for i, song := range response.Songs {
+			rows = append(rows, table.Row{
+				fmt.Sprintf("%d", offset+i+1),
+				song.Name,
+				song.Artist,
+				song.Album,
+				fmt.Sprintf("%d", song.Popularity),
+			})
+		}
+		return rows
+	},
+	footer: theme.HelpStyle.Render("[↑/↓] Navigate  [tab] Next Slider  [←/→] Adjust  [x] Clear Slider  [/] Filter  [o/O] Sort  [ctrl+s] Save as Playlist  [enter] Details  [q] Back  [H] Home"),
+}
+
+// fetchRecommendations fetches the recommended tracks. They come as a single
+// page.
+func fetchRecommendations(url string) (APIResponse, error) {
+	token, _ := auth.GetValidAccessToken()
+	response, err := MakeAPIRequest(token, url)
+	if err != nil {
+		return APIResponse{}, err
+	}
+
+	var songs []Song
+	if items, ok := response["tracks"].([]interface{}); ok {
+		songs = parseSongItems(items, 0)
+	}
+	return APIResponse{Songs: songs}, nil
+}
+
+type recommendationsMsg struct {
+	seq      int
+	url      string
+	response APIResponse
+	err      error
+}
+
+// recommendationsDebounceMsg fetches the recommendations once the sliders
+// have rested.
+type recommendationsDebounceMsg struct {
+	seq int
+}
+
+// fetchRecommendationsCmd fetches the recommendations for the current seeds
+// and sliders.
+func (m *appModel) fetchRecommendationsCmd() tea.Cmd {
+	m.recs.seq++
+	m.recs.loading = true
+	seq, url := m.recs.seq, m.recs.url()
+	return func() tea.Msg {
+		response, err := fetchRecommendations(url)
+		return recommendationsMsg{seq, url, response, err}
+	}
+}
+
+// selectedSeed returns the top artist or song under the cursor as a seed.
+func (m appModel) selectedSeed() (seed, bool) {
+	switch m.currentView {
+	case viewArtists:
+		if i := m.artistList.index(m.artistTable.Cursor()); i >= 0 && i < len(m.artists.Artists) {
+			artist := m.artists.Artists[i]
+			return seed{artist: true, id: artist.ID, name: artist.Name}, true
+		}
+	case viewSongs:
+		if i := m.songList.index(m.songTable.Cursor()); i >= 0 && i < len(m.songs.Songs) {
+			song := m.songs.Songs[i]
+			return seed{id: song.ID, name: song.Name}, true
+		}
+	}
+	return seed{}, false
+}
+
+// isSeed reports whether the artist or track is a seed.
+func (m appModel) isSeed(id string) bool {
+	for _, s := range m.recs.seeds {
+		if s.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isArtistSeedRow and isSongSeedRow report whether a row of the top tables is
+// a seed, to highlight it.
+func (m appModel) isArtistSeedRow(row int) bool {
+	i := m.artistList.index(row)
+	return i >= 0 && i < len(m.artists.Artists) && m.isSeed(m.artists.Artists[i].ID)
+}
+
+func (m appModel) isSongSeedRow(row int) bool {
+	i := m.songList.index(row)
+	return i >= 0 && i < len(m.songs.Songs) && m.isSeed(m.songs.Songs[i].ID)
+}
+
+// toggleSeed marks or unmarks the top artist or song under the cursor as a
+// seed of the recommendations.
+func (m *appModel) toggleSeed() tea.Cmd {
+	s, ok := m.selectedSeed()
+	if !ok {
+		return nil
+	}
+
+	for i, existing := range m.recs.seeds {
+		if existing.id == s.id {
+			m.recs.seeds = append(m.recs.seeds[:i:i], m.recs.seeds[i+1:]...)
+			return m.showToast(fmt.Sprintf("Removed %s from the seeds (%d/%d)", s.name, len(m.recs.seeds), maxSeeds))
+		}
+	}
+	if len(m.recs.seeds) >= maxSeeds {
+		return m.showToast(fmt.Sprintf("At most %d artists and tracks can be seeds", maxSeeds))
+	}
+	m.recs.seeds = append(m.recs.seeds, s)
+	return m.showToast(fmt.Sprintf("Added %s to the seeds (%d/%d)", s.name, len(m.recs.seeds), maxSeeds))
+}
+
+// openRecommendations shows the recommendations for the seeds, seeding them
+// with the item under the cursor if there are none yet.
+func (m *appModel) openRecommendations() tea.Cmd {
+	if len(m.recs.seeds) == 0 {
+		s, ok := m.selectedSeed()
+		if !ok {
+			return nil
+		}
+		m.recs.seeds = []seed{s}
+	}
+
+	l := m.lists[viewRecommendations]
+	l.response, l.pages = APIResponse{}, nil
+	l.list.set(&l.table, nil)
+	l.table.SetCursor(0)
+	m.recs.err = nil
+	m.pushView(viewRecommendations)
+	return m.fetchRecommendationsCmd()
+}
+
+// updateRecommendations handles the slider keys of the recommendations view.
+// It reports false for other keys.
+func (m *appModel) updateRecommendations(msg tea.KeyMsg) (tea.Cmd, bool) {
+	r := &m.recs
+	s := &r.sliders[r.focus]
+
+	switch msg.String() {
+	case "tab", "shift+tab":
+		step := 1
+		if msg.String() == "shift+tab" {
+			step = len(r.sliders) - 1
+		}
+		r.focus = (r.focus + step) % len(r.sliders)
+		return nil, true
+	case "left", "right":
+		if s.set {
+			step := s.step
+			if msg.String() == "left" {
+				step = -step
+			}
+			s.value = max(s.min, min(s.value+step, s.max))
+		}
+		s.set = true
+	case "x":
+		if !s.set {
+			return nil, true
+		}
+		s.set = false
+	case "ctrl+s":
+		return m.saveRecommendations(), true
+	default:
+		return nil, false
+	}
+
+	r.seq++
+	seq := r.seq
+	return tea.Tick(recommendationsDebounce, func(time.Time) tea.Msg {
+		return recommendationsDebounceMsg{seq}
+	}), true
+}
+
+// refetchRecommendations fetches the recommendations once the sliders have
+// rested.
+func (m *appModel) refetchRecommendations(msg recommendationsDebounceMsg) tea.Cmd {
+	if msg.seq != m.recs.seq {
+		return nil
+	}
+	return m.fetchRecommendationsCmd()
+}
+
+// showRecommendations shows fetched recommendations, keeping the cursor on the
+// selected track where it is still recommended.
+func (m *appModel) showRecommendations(msg recommendationsMsg) {
+	if msg.seq != m.recs.seq {
+		return
+	}
+	m.recs.loading = false
+	m.recs.err = msg.err
+	if msg.err != nil {
+		return
+	}
+
+	m.lists[viewRecommendations].pages = []string{msg.url}
+	m.replacePage(viewRecommendations, msg.response)
+}
+
+// saveRecommendations asks for a playlist name to save the recommendations
+// to, as they are currently filtered and sorted.
+func (m *appModel) saveRecommendations() tea.Cmd {
+	l := m.lists[viewRecommendations]
+	var songs []Song
+	for row := range l.list.order {
+		if i := l.list.index(row); i >= 0 && i < len(l.response.Songs) {
+			songs = append(songs, l.response.Songs[i])
+		}
+	}
+	if len(songs) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(m.recs.seeds))
+	for i, s := range m.recs.seeds {
+		names[i] = s.name
+	}
+	return m.prompt.open("Recommended · "+strings.Join(names, ", "), songs)
+}
+
+// renderSlider renders a slider as a bar with a marker at its value.
+func renderSlider(s slider, focused bool) string {
+	label := fmt.Sprintf("%-12s", s.label)
+	if focused {
+		label = theme.HighlightStyle.Render("› " + label)
+	} else {
+		label = "  " + label
+	}
+	if !s.set {
+		return label + theme.MutedStyle.Render(strings.Repeat("─", sliderWidth)+" any")
+	}
+
+	pos := int((s.value - s.min) / (s.max - s.min) * (sliderWidth - 1))
+	bar := theme.HighlightStyle.Render(strings.Repeat("━", pos)+"●") + theme.MutedStyle.Render(strings.Repeat("─", sliderWidth-pos-1))
+	return label + bar + " " + fmt.Sprintf(s.format, s.value)
+}
+
+func (m appModel) renderRecommendations() string {
+	l := m.lists[viewRecommendations]
+	r := m.recs
+
+	names := make([]string, len(r.seeds))
+	for i, s := range r.seeds {
+		names[i] = s.name
+	}
+	lines := []string{theme.MutedStyle.Render("  Seeds: " + strings.Join(names, " · "))}
+	for i, s := range r.sliders {
+		lines = append(lines, renderSlider(s, i == r.focus))
+	}
+
+	status := ""
+	switch {
+	case r.err != nil:
+		status = "  Could not fetch recommendations: " + r.err.Error()
+	case r.loading:
+		status = theme.MutedStyle.Render("  Loading…")
+	}
+	lines = append(lines, status)
+
+	bar := l.list.renderFilter()
+	chrome := tableChrome + len(lines)
+	if bar != "" {
+		chrome++
+	}
+	body := bar + m.renderTableStyled(l.table, l.widths, chrome, nil, l.list.match)
+	return theme.TitleStyle.Render(m.top().title) + "\n" + strings.Join(lines, "\n") + "\n" + body + "\n" + recommendationsSource.footer
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Tabs of the search view
+const (
+	searchTracks = iota
+	searchArtists
+	searchAlbums
+	searchPlaylists
+)
+
+var searchTabTitles = []string{"Tracks", "Artists", "Albums", "Playlists"}
+
+// Relative column widths of the search result tables
+var searchColRatios = [][]float64{
+	{0.4, 0.25, 0.25, 0.1},
+	{0.35, 0.5, 0.15},
+	{0.4, 0.3, 0.15, 0.15},
+	{0.5, 0.35, 0.15},
+}
+
+// searchDebounce is how long typing has to pause before a search is sent.
+const searchDebounce = 300 * time.Millisecond
+
+// searchResults holds the results of one query, per type.
+type searchResults struct {
+	songs     []Song
+	artists   []Artist
+	albums    []Album
+	playlists []Playlist
+}
+
+// searchState is the state of the search view.
+type searchState struct {
+	input   textinput.Model
+	seq     int // Incremented on every edit; only the latest query's results are shown
+	query   string
+	tab     int
+	results searchResults
+	tables  [4]table.Model
+	err     error
+}
+
+type searchDebounceMsg struct {
+	seq int
+}
+
+type searchResultsMsg struct {
+	seq     int
+	results searchResults
+	err     error
+}
+
+func newSearchState() searchState {
+	ti := textinput.New()
+	ti.Placeholder = "Search tracks, artists, albums and playlists"
+	ti.Prompt = "/ "
+	ti.CharLimit = 200
+	ti.Width = 60
+	ti.Focus()
+
+	s := searchState{input: ti}
+	s.setResults(searchResults{})
+	return s
+}
+
+// setResults stores new results and rebuilds the result tables.
+func (s *searchState) setResults(results searchResults) {
+	s.results = results
+
+	var trackRows, artistRows, albumRows, playlistRows []table.Row
+	for _, song := range results.songs {
+		trackRows = append(trackRows, table.Row{song.Name, song.Artist, song.Album, fmt.Sprintf("%d", song.Popularity)})
+	}
+	for _, artist := range results.artists {
+		artistRows = append(artistRows, table.Row{artist.Name, artist.Genres, fmt.Sprintf("%d", artist.Popularity)})
+	}
+	for _, album := range results.albums {
+		albumRows = append(albumRows, table.Row{album.Name, album.Artist, album.ReleaseDate, album.AlbumType})
+	}
+	for _, playlist := range results.playlists {
+		playlistRows = append(playlistRows, table.Row{playlist.Name, playlist.Owner, fmt.Sprintf("%d", playlist.TrackCount)})
+	}
+
+	newTable := func(columns []string, rows []table.Row) table.Model {
+		cols := make([]table.Column, len(columns))
+		for i, title := range columns {
+			cols[i] = table.Column{Title: title, Width: 20}
+		}
+		return table.New(table.WithColumns(cols), table.WithRows(rows), table.WithFocused(true))
+	}
+
+	s.tables = [4]table.Model{
+		newTable([]string{"Name", "Artist", "Album", "Popularity"}, trackRows),
+		newTable([]string{"Name", "Genres", "Popularity"}, artistRows),
+		newTable([]string{"Name", "Artist", "Released", "Type"}, albumRows),
+		newTable([]string{"Name", "Owner", "Tracks"}, playlistRows),
+	}
+}
+
+// searchCatalog queries /v1/search for every result type at once. Field
+// filters such as artist: and year: are part of the query and are passed
+// through untouched.
+func searchCatalog(query string) (searchResults, error) {
+	token, _ := auth.GetValidAccessToken()
+	response, err := MakeAPIRequest(token, "https://api.spotify.com/v1/search?type=track,artist,album,playlist&limit=20&q="+url.QueryEscape(query))
+	if err != nil {
+		return searchResults{}, err
+	}
+
+	var results searchResults
+	if tracks, ok := response["tracks"].(map[string]interface{}); ok {
+		results.songs = parseSongs(tracks)
+	}
+	if artists, ok := response["artists"].(map[string]interface{}); ok {
+		results.artists = parseArtists(artists)
+	}
+	if albums, ok := response["albums"].(map[string]interface{}); ok {
+		if items, ok := albums["items"].([]interface{}); ok {
+			results.albums = parseAlbumItems(items)
+		}
+	}
+	if playlists, ok := response["playlists"].(map[string]interface{}); ok {
+		results.playlists = parsePlaylists(playlists)
+	}
+
+	return results, nil
+}
+
+// openSearch switches to the search view, keeping the previous query.
+func (m *appModel) openSearch() tea.Cmd {
+	m.search.input.Focus()
+	m.pushView(viewSearch)
+	return textinput.Blink
+}
+
+// updateSearch handles keys in the search view. Printable keys go to the
+// query, so the usual single-letter shortcuts do not apply here.
+func (m appModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.search
+
+	switch msg.String() {
+	case "esc":
+		m.pop()
+		return m, nil
+
+	case "tab", "shift+tab":
+		step := 1
+		if msg.String() == "shift+tab" {
+			step = len(searchTabTitles) - 1
+		}
+		s.tab = (s.tab + step) % len(searchTabTitles)
+		return m, nil
+
+	case "up", "down", "pgup", "pgdown":
+		var cmd tea.Cmd
+		s.tables[s.tab], cmd = s.tables[s.tab].Update(msg)
+		return m, cmd
+
+	case "enter":
+		return m, m.openSearchResult()
+	}
+
+	var cmd tea.Cmd
+	s.input, cmd = s.input.Update(msg)
+	if query := strings.TrimSpace(s.input.Value()); query != s.query {
+		s.query = query
+		s.seq++
+		seq := s.seq
+		return m, tea.Batch(cmd, tea.Tick(searchDebounce, func(time.Time) tea.Msg {
+			return searchDebounceMsg{seq}
+		}))
+	}
+	return m, cmd
+}
+
+// runSearch sends the query once typing has paused.
+func (m appModel) runSearch(msg searchDebounceMsg) tea.Cmd {
+	query := m.search.query
+	if msg.seq != m.search.seq || query == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		results, err := searchCatalog(query)
+		return searchResultsMsg{msg.seq, results, err}
+	}
+}
+
+// openSearchResult opens the detail view of the selected result.
+func (m *appModel) openSearchResult() tea.Cmd {
+	s := m.search
+	i := s.tables[s.tab].Cursor()
+
+	switch s.tab {
+	case searchTracks:
+		if i >= 0 && i < len(s.results.songs) {
+			song := s.results.songs[i]
+			m.push(navEntry{view: viewTrackDetail, title: song.Name, track: song})
+		}
+	case searchArtists:
+		if i >= 0 && i < len(s.results.artists) {
+			return fetchArtistDetailCmd(s.results.artists[i].ID)
+		}
+	case searchAlbums:
+		if i >= 0 && i < len(s.results.albums) {
+			return fetchAlbumCmd(s.results.albums[i].ID)
+		}
+	case searchPlaylists:
+		if i >= 0 && i < len(s.results.playlists) {
+			return m.openPlaylist(s.results.playlists[i])
+		}
+	}
+	return nil
+}
+
+func (m appModel) renderSearch() string {
+	s := m.search
+
+	tabs := make([]string, len(searchTabTitles))
+	counts := []int{len(s.results.songs), len(s.results.artists), len(s.results.albums), len(s.results.playlists)}
+	for i, tabTitle := range searchTabTitles {
+		label := fmt.Sprintf("%s (%d)", tabTitle, counts[i])
+		if i == s.tab {
+			tabs[i] = theme.HighlightStyle.Render("[" + label + "]")
+		} else {
+			tabs[i] = theme.MutedStyle.Render(" " + label + " ")
+		}
+	}
+
+	status := theme.MutedStyle.Render("  Filters: artist:  album:  track:  year:1990-1999  genre:  tag:new")
+	if s.err != nil {
+		status = "  Search failed: " + s.err.Error()
+	}
+
+	width := max(m.windowSize.Width-10, 20)
+	body := m.renderTableWithin(s.tables[s.tab], calculateColumnWidths(width, searchColRatios[s.tab]), tableChrome+4)
+
+	return theme.TitleStyle.Render("Search") + "\n  " + s.input.View() + "\n" + status + "\n\n  " +
+		strings.Join(tabs, " ") + "\n" + body + "\n" +
+		theme.HelpStyle.Render("[type] Search  [↑/↓] Navigate  [tab] Next Type  [enter] Open  [esc] Back")
+}
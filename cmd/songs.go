@@ -2,13 +2,99 @@ package cmd
 
 import (
 	"github.com/bytegrunt/go-spotify-me/internal/auth"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 type Song struct {
-	Name       string
-	Artist     string
-	Album      string
-	Popularity int
+	ID          string   `json:"id"`
+	URI         string   `json:"uri"`
+	Rank        int      `json:"rank"` // Position in the list the song was fetched from, starting at 1
+	Name        string   `json:"name"`
+	Artist      string   `json:"artist"` // First credited artist
+	Artists     []string `json:"artists"`
+	ArtistIDs   []string `json:"artist_ids"`
+	Album       string   `json:"album"`
+	AlbumID     string   `json:"album_id"`
+	ReleaseDate string   `json:"release_date"`
+	TrackNumber int      `json:"track_number"`
+	DiscNumber  int      `json:"disc_number"`
+	DurationMs  int      `json:"duration_ms"`
+	Explicit    bool     `json:"explicit"`
+	ISRC        string   `json:"isrc,omitempty"`
+	PreviewURL  string   `json:"preview_url,omitempty"`
+	URL         string   `json:"url"`
+	Popularity  int      `json:"popularity"`
+	AddedAt     string   `json:"added_at,omitempty"`  // When the song was saved or added to a playlist
+	PlayedAt    string   `json:"played_at,omitempty"` // When the song was played, for recently played tracks
+}
+
+// topSongsURL returns the URL of the first page of the user's top tracks.
+func topSongsURL(timeRange string) string {
+	return "https://api.spotify.com/v1/me/top/tracks?time_range=" + timeRange
+}
+
+// fetchSongsCmd fetches a page of songs and switches to the Songs view from
+// the given view.
+func fetchSongsCmd(url string, from viewType) tea.Cmd {
+	return func() tea.Msg {
+		response, err := fetchSongsPage(url)
+		if err != nil {
+			return errMsg{err}
+		}
+		return switchToSongsMsg{response, from}
+	}
+}
+
+// fetchAllSongsCmd fetches every top song for the time range and switches to
+// the Songs view from the given view.
+func fetchAllSongsCmd(timeRange string, from viewType) tea.Cmd {
+	return func() tea.Msg {
+		songs, err := fetchAllSongs(timeRange)
+		if err != nil {
+			return errMsg{err}
+		}
+		return switchToSongsMsg{APIResponse{Songs: songs}, from}
+	}
+}
+
+// fetchAllSongs follows the pages of the user's top tracks until Spotify stops
+// returning a next page, dropping any track that appears twice.
+func fetchAllSongs(timeRange string) ([]Song, error) {
+	all, err := collectSongs(topSongsURL(timeRange) + "&limit=" + topItemsPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var songs []Song
+	seen := make(map[string]bool)
+	for _, song := range all {
+		if seen[song.ID] {
+			continue
+		}
+		seen[song.ID] = true
+		songs = append(songs, song)
+	}
+
+	return songs, nil
+}
+
+// collectSongs follows the next links from url and returns the songs of every
+// page, in order. If there are more than maxPages pages, the songs of the
+// first ones are returned with errPageLimit.
+func collectSongs(url string) ([]Song, error) {
+	var songs []Song
+	for page := 0; url != ""; page++ {
+		if page == maxPages {
+			return songs, errPageLimit
+		}
+		response, err := fetchSongsPage(url)
+		if err != nil {
+			return nil, err
+		}
+		songs = append(songs, response.Songs...)
+		url = response.Next
+	}
+	return songs, nil
 }
 
 func fetchSongsPage(url string) (APIResponse, error) {
@@ -35,33 +121,92 @@ func parseSongs(response map[string]interface{}) []Song {
 		return nil
 	}
 
+	offset, _ := response["offset"].(float64)
+	return parseSongItems(items, int(offset))
+}
+
+// parseSongItems parses a list of track objects, ranking them from offset+1.
+func parseSongItems(items []interface{}, offset int) []Song {
 	var songs []Song
-	for _, item := range items {
+	for i, item := range items {
 		track, ok := item.(map[string]interface{})
 		if !ok {
 			continue
 		}
 
-		name := track["name"].(string)
-		popularity := int(track["popularity"].(float64))
+		// Saved, played and playlist tracks wrap the track object
+		addedAt, _ := track["added_at"].(string)
+		playedAt, _ := track["played_at"].(string)
+		if _, wrapped := track["track"]; wrapped {
+			if track, ok = track["track"].(map[string]interface{}); !ok {
+				continue
+			}
+		}
+
+		id, _ := track["id"].(string)
+		uri, _ := track["uri"].(string)
+		name, _ := track["name"].(string)
+		popularity, _ := track["popularity"].(float64)
+		trackNumber, _ := track["track_number"].(float64)
+		discNumber, _ := track["disc_number"].(float64)
+		durationMs, _ := track["duration_ms"].(float64)
+		explicit, _ := track["explicit"].(bool)
+		previewURL, _ := track["preview_url"].(string)
 
-		albumName := ""
+		albumName, albumID, releaseDate := "", "", ""
 		if album, ok := track["album"].(map[string]interface{}); ok {
 			albumName, _ = album["name"].(string)
+			albumID, _ = album["id"].(string)
+			releaseDate, _ = album["release_date"].(string)
 		}
 
-		artistName := ""
-		if artistList, ok := track["artists"].([]interface{}); ok && len(artistList) > 0 {
-			if firstArtist, ok := artistList[0].(map[string]interface{}); ok {
-				artistName, _ = firstArtist["name"].(string)
+		var artistNames, artistIDs []string
+		if artistList, ok := track["artists"].([]interface{}); ok {
+			for _, a := range artistList {
+				if artist, ok := a.(map[string]interface{}); ok {
+					artistName, _ := artist["name"].(string)
+					artistID, _ := artist["id"].(string)
+					artistNames = append(artistNames, artistName)
+					artistIDs = append(artistIDs, artistID)
+				}
 			}
 		}
+		artistName := ""
+		if len(artistNames) > 0 {
+			artistName = artistNames[0]
+		}
+
+		isrc := ""
+		if externalIDs, ok := track["external_ids"].(map[string]interface{}); ok {
+			isrc, _ = externalIDs["isrc"].(string)
+		}
+
+		url := ""
+		if externalURLs, ok := track["external_urls"].(map[string]interface{}); ok {
+			url, _ = externalURLs["spotify"].(string)
+		}
 
 		songs = append(songs, Song{
-			Name:       name,
-			Artist:     artistName,
-			Album:      albumName,
-			Popularity: popularity,
+			ID:          id,
+			URI:         uri,
+			Rank:        offset + i + 1,
+			Name:        name,
+			Artist:      artistName,
+			Artists:     artistNames,
+			ArtistIDs:   artistIDs,
+			Album:       albumName,
+			AlbumID:     albumID,
+			ReleaseDate: releaseDate,
+			TrackNumber: int(trackNumber),
+			DiscNumber:  int(discNumber),
+			DurationMs:  int(durationMs),
+			Explicit:    explicit,
+			ISRC:        isrc,
+			PreviewURL:  previewURL,
+			URL:         url,
+			Popularity:  int(popularity),
+			AddedAt:     addedAt,
+			PlayedAt:    playedAt,
 		})
 	}
 
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bytegrunt/go-spotify-me/internal/logging"
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toastDuration is how long a toast stays in the status line.
+const toastDuration = 3 * time.Second
+
+// Intervals between polls of the currently playing track
+const (
+	pollPlaying = 5 * time.Second
+	pollPaused  = 15 * time.Second
+	pollIdle    = 30 * time.Second
+	pollMin     = time.Second
+)
+
+// pollTickMsg asks for the currently playing track to be polled.
+type pollTickMsg struct{}
+
+// progressTickMsg redraws the status line so its progress bar moves between
+// polls.
+type progressTickMsg struct{}
+
+// toastExpiredMsg clears the toast it was scheduled for.
+type toastExpiredMsg struct {
+	seq int
+}
+
+type currentlyPlayingMsg struct {
+	state playbackState
+	err   error
+}
+
+// startStatusLine starts polling the currently playing track and ticking its
+// progress.
+func startStatusLine() tea.Cmd {
+	return tea.Batch(pollCurrentlyPlayingCmd(), tickProgressCmd())
+}
+
+func tickProgressCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return progressTickMsg{}
+	})
+}
+
+// schedulePollCmd polls again after an interval suited to the player state.
+func schedulePollCmd(state playbackState) tea.Cmd {
+	return tea.Tick(pollInterval(state), func(time.Time) tea.Msg {
+		return pollTickMsg{}
+	})
+}
+
+// pollInterval polls often while a track plays, and more so just before it
+// ends so the next track shows up promptly, and rarely while nothing plays.
+// Playing with no known duration, as during an ad, is polled as usual.
+func pollInterval(state playbackState) time.Duration {
+	switch {
+	case !state.active:
+		return pollIdle
+	case !state.playing:
+		return pollPaused
+	case state.track.DurationMs <= 0:
+		return pollPlaying
+	}
+
+	remaining := time.Duration(state.track.DurationMs-state.progressNow()) * time.Millisecond
+	if remaining < pollPlaying {
+		return max(remaining+500*time.Millisecond, pollMin)
+	}
+	return pollPlaying
+}
+
+// pollCurrentlyPlayingCmd fetches the full player state, so the device,
+// volume, shuffle and repeat state stay current along with the track.
+func pollCurrentlyPlayingCmd() tea.Cmd {
+	return func() tea.Msg {
+		state, err := fetchPlaybackState()
+		return currentlyPlayingMsg{state, err}
+	}
+}
+
+// updateCurrentlyPlaying stores the polled player state and schedules the next
+// poll.
+func (m *appModel) updateCurrentlyPlaying(msg currentlyPlayingMsg) tea.Cmd {
+	if msg.err != nil {
+		logging.DebugLog("Failed to poll the player state: %v", msg.err)
+		return schedulePollCmd(m.playback)
+	}
+
+	m.playback = msg.state
+	return schedulePollCmd(m.playback)
+}
+
+// showToast shows a short confirmation in the status line in place of the
+// currently playing track.
+func (m *appModel) showToast(text string) tea.Cmd {
+	m.toast = text
+	m.toastSeq++
+	seq := m.toastSeq
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{seq}
+	})
+}
+
+// renderStatusLine renders the current toast, or else the currently playing
+// track, shown below every view.
+func (m appModel) renderStatusLine() string {
+	if m.prompt.active() {
+		return m.prompt.view()
+	}
+	if m.toast != "" {
+		return "  " + theme.HighlightStyle.Render(m.toast)
+	}
+
+	p := m.playback
+	if !p.active || p.track.Name == "" {
+		return theme.MutedStyle.Render("  ♪ Nothing playing")
+	}
+
+	icon := "⏸"
+	if p.playing {
+		icon = "▶"
+	}
+	track := icon + " " + p.track.Name
+	if len(p.track.Artists) > 0 {
+		track += " — " + strings.Join(p.track.Artists, ", ")
+	}
+
+	width := max(m.windowSize.Width-len([]rune(track))-20, 10)
+	return "  " + theme.HighlightStyle.Render(track) + "  " + renderProgressBar(p.progressNow(), p.track.DurationMs, min(width, 40))
+}
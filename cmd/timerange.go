@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Spotify time ranges for the /me/top endpoints
+const (
+	shortTerm  = "short_term"
+	mediumTerm = "medium_term"
+	longTerm   = "long_term"
+)
+
+var timeRanges = []string{shortTerm, mediumTerm, longTerm}
+
+// topItemsPageSize is the largest page Spotify serves for the /me/top
+// endpoints.
+const topItemsPageSize = "50"
+
+// parseTimeRange accepts either the short form ("short") or the Spotify
+// time_range value ("short_term").
+func parseTimeRange(s string) (string, error) {
+	for _, r := range timeRanges {
+		if s == r || s+"_term" == r {
+			return r, nil
+		}
+	}
+	return "", fmt.Errorf("invalid time range %q, expected one of: %s", s, strings.Join(timeRangeNames(), ", "))
+}
+
+// timeRangeNames returns the short forms accepted by parseTimeRange.
+func timeRangeNames() []string {
+	names := make([]string, len(timeRanges))
+	for i, r := range timeRanges {
+		names[i] = strings.TrimSuffix(r, "_term")
+	}
+	return names
+}
+
+// timeRangeLabel returns a human readable label for a time range.
+func timeRangeLabel(timeRange string) string {
+	switch timeRange {
+	case shortTerm:
+		return "Last 4 Weeks"
+	case longTerm:
+		return "All Time"
+	default:
+		return "Last 6 Months"
+	}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytegrunt/go-spotify-me/internal/theme"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// selectedSong returns the song under the cursor of the current view.
+func (m appModel) selectedSong() (Song, bool) {
+	switch m.currentView {
+	case viewSongs:
+		if i := m.songList.index(m.songTable.Cursor()); i >= 0 && i < len(m.songs.Songs) {
+			return m.songs.Songs[i], true
+		}
+	case viewRecent, viewLiked, viewPlaylistTracks, viewQueue, viewRecommendations:
+		if songs, i := m.lists[m.currentView].response.Songs, m.selectedIndex(m.currentView); i >= 0 && i < len(songs) {
+			return songs[i], true
+		}
+	case viewArtistDetail:
+		d := m.top().artist
+		if i := d.tables[artistTopTracks].Cursor(); d.section == artistTopTracks && i >= 0 && i < len(d.topTracks) {
+			return d.topTracks[i], true
+		}
+	case viewAlbum:
+		d := m.top().album
+		if i := d.table.Cursor(); i >= 0 && i < len(d.tracks) {
+			return d.tracks[i], true
+		}
+	case viewTrackDetail:
+		return m.top().track, true
+	}
+	return Song{}, false
+}
+
+// openTrackDetail shows the song under the cursor.
+func (m *appModel) openTrackDetail() bool {
+	song, ok := m.selectedSong()
+	if !ok || m.currentView == viewTrackDetail {
+		return false
+	}
+	m.push(navEntry{view: viewTrackDetail, title: song.Name, track: song})
+	return true
+}
+
+// openTrackArtist opens the detail of the track's first artist, going back to
+// it if the track was opened from there.
+func (m *appModel) openTrackArtist() tea.Cmd {
+	t := m.top().track
+	if len(t.ArtistIDs) == 0 || t.ArtistIDs[0] == "" || m.backTo(viewArtistDetail, t.ArtistIDs[0]) {
+		return nil
+	}
+	return fetchArtistDetailCmd(t.ArtistIDs[0])
+}
+
+// openTrackAlbum opens the album of the track, going back to it if the track
+// was opened from there.
+func (m *appModel) openTrackAlbum() tea.Cmd {
+	if m.backTo(viewAlbum, m.top().track.AlbumID) {
+		return nil
+	}
+	return m.openAlbum()
+}
+
+func (m appModel) renderTrackDetail() string {
+	t := m.top().track
+
+	explicit := "No"
+	if t.Explicit {
+		explicit = "Yes"
+	}
+	preview := "Not available"
+	if t.PreviewURL != "" {
+		preview = "Available"
+	}
+
+	rows := [][]string{
+		{"Title", t.Name},
+		{"Artists", strings.Join(t.Artists, ", ")},
+		{"Album", t.Album},
+		{"Release Date", t.ReleaseDate},
+		{"Track", fmt.Sprintf("%d (disc %d)", t.TrackNumber, t.DiscNumber)},
+		{"Duration", formatDuration(t.DurationMs)},
+		{"Explicit", explicit},
+		{"Popularity", fmt.Sprintf("%d", t.Popularity)},
+		{"ISRC", t.ISRC},
+		{"Preview", preview},
+		{"Spotify URL", t.URL},
+	}
+
+	width := max(m.windowSize.Width, 20)
+	colWidths := calculateColumnWidths(width, []float64{0.3, 0.7})
+
+	var renderedRows []string
+	header := theme.RenderRow([]string{"Field", "Value"}, colWidths, theme.HeaderStyle)
+	for _, row := range rows {
+		renderedRows = append(renderedRows, theme.RenderRow(row, colWidths, theme.RowStyle))
+	}
+
+	body := header + "\n" + strings.Join(renderedRows, "\n")
+	return theme.TitleStyle.Render(t.Name) + "\n" + theme.TableContainerStyle.Render(body) + "\n" +
+		theme.HelpStyle.Render("[a] Artist  [b] Album  [i] Add to Playlist  [e] Queue  [q] Back  [H] Home")
+}
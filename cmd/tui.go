@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zalando/go-keyring"
+)
+
+// startViews are the --view names and the views they open, in the order
+// they are listed in.
+var startViews = []struct {
+	name string
+	view viewType
+}{
+	{"menu", viewMenu},
+	{"artists", viewArtists},
+	{"songs", viewSongs},
+	{"recent", viewRecent},
+	{"liked", viewLiked},
+	{"albums", viewSavedAlbums},
+	{"following", viewFollowed},
+	{"playlists", viewPlaylists},
+}
+
+func startViewNames() []string {
+	names := make([]string, len(startViews))
+	for i, s := range startViews {
+		names[i] = s.name
+	}
+	return names
+}
+
+// lookupStartView returns the view a --view name opens.
+func lookupStartView(name string) (viewType, bool) {
+	for _, s := range startViews {
+		if s.name == name {
+			return s.view, true
+		}
+	}
+	return viewMenu, false
+}
+
+// StartView selects the view and time range the TUI opens on.
+type StartView struct {
+	View  string
+	Range string
+}
+
+// GetDefaultStartView retrieves the configured start view from the keyring or
+// environment variables, defaulting to the menu. A default that is not valid,
+// such as one saved by another version, is warned about and ignored rather
+// than keeping the TUI from starting.
+func GetDefaultStartView() StartView {
+	start := StartView{View: "menu", Range: mediumTerm}
+
+	if view, source := configuredDefault("default_view", "SPOTIFY_DEFAULT_VIEW"); view != "" {
+		if _, ok := lookupStartView(view); ok {
+			start.View = view
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring invalid default view %q from %s, expected one of: %s\n", view, source, strings.Join(startViewNames(), ", "))
+		}
+	}
+
+	if timeRange, source := configuredDefault("default_range", "SPOTIFY_DEFAULT_RANGE"); timeRange != "" {
+		if parsed, err := parseTimeRange(timeRange); err == nil {
+			start.Range = parsed
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring default range from %s: %v\n", source, err)
+		}
+	}
+
+	return start
+}
+
+// configuredDefault returns a default from the keyring, or else from the
+// environment variable, along with where it came from.
+func configuredDefault(key, env string) (string, string) {
+	if value, err := keyring.Get("go-spotify-me-cli", key); err == nil {
+		return value, "the keyring"
+	}
+	return os.Getenv(env), env
+}
+
+// saveDefaultStartView stores the start view in the keyring.
+func saveDefaultStartView(start StartView) error {
+	if err := keyring.Set("go-spotify-me-cli", "default_view", start.View); err != nil {
+		return fmt.Errorf("failed to store default view in keyring: %w", err)
+	}
+	if err := keyring.Set("go-spotify-me-cli", "default_range", start.Range); err != nil {
+		return fmt.Errorf("failed to store default range in keyring: %w", err)
+	}
+	return nil
+}
+
+// validate normalizes the start view, returning an error for unknown values.
+func (s StartView) validate() (StartView, error) {
+	if _, ok := lookupStartView(s.View); !ok {
+		return s, fmt.Errorf("invalid view %q, expected one of: %s", s.View, strings.Join(startViewNames(), ", "))
+	}
+	timeRange, err := parseTimeRange(s.Range)
+	if err != nil {
+		return s, err
+	}
+	s.Range = timeRange
+	return s, nil
+}
+
+func tuiCommand() command {
+	return command{
+		name:  "tui",
+		usage: "tui [--view " + strings.Join(startViewNames(), "|") + "] [--range short|medium|long] [--save-default]",
+		flags: func(fs *flag.FlagSet) func(args []string) error {
+			view := fs.String("view", "", "view to open on start (default: the saved default view, or menu)")
+			timeRange := fs.String("range", "", "time range for top artists and songs (default: the saved default range, or medium)")
+			saveDefault := fs.Bool("save-default", false, "remember --view and --range as the default start view")
+
+			return func(args []string) error {
+				start := GetDefaultStartView()
+				if *view != "" {
+					start.View = *view
+				}
+				if *timeRange != "" {
+					start.Range = *timeRange
+				}
+				start, err := start.validate()
+				if err != nil {
+					return err
+				}
+				if *saveDefault {
+					if err := saveDefaultStartView(start); err != nil {
+						return err
+					}
+				}
+				return runTUI(start)
+			}
+		},
+		complete: func(flag string) []string {
+			switch flag {
+			case "view":
+				return startViewNames()
+			case "range":
+				return timeRangeNames()
+			}
+			return nil
+		},
+	}
+}
+
+func runTUI(start StartView) error {
+	clientID, err := GetClientID()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve client ID: %w", err)
+	}
+
+	// Initialize the app model with the client ID
+	p := tea.NewProgram(InitialAppModel(clientID, start), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error starting TUI: %w", err)
+	}
+	return nil
+}
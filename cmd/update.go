@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/charmbracelet/bubbles/table"
@@ -9,18 +10,26 @@ import (
 )
 
 type APIResponse struct {
-	Artists []Artist
-	Songs   []Song
-	Next    string
-	Prev    string
+	Artists   []Artist
+	Songs     []Song
+	Albums    []Album
+	Playlists []Playlist
+	Devices   []Device
+	Next      string
+	Prev      string
 }
 
+// switchToArtistsMsg and switchToSongsMsg carry fetched top artists or songs.
+// From is the view they were fetched from; if the user has since moved on to
+// another view, they are dropped.
 type switchToArtistsMsg struct {
 	response APIResponse
+	from     viewType
 }
 
 type switchToSongsMsg struct {
 	response APIResponse
+	from     viewType
 }
 
 type errMsg struct {
@@ -32,154 +41,294 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// The search view takes printable keys as input
+		if m.currentView == viewSearch {
+			return m.updateSearch(msg)
+		}
+
+		// So does an open filter bar
+		if list, t := m.currentList(); list != nil && list.filtering() {
+			return m, list.update(t, msg)
+		}
+
+		// And the prompt for a playlist name
+		if m.prompt.active() {
+			return m, m.prompt.update(msg)
+		}
+
+		// Playback keys work from every view once logged in
+		if m.currentView != viewEnterClientID {
+			if cmd, ok := m.updatePlayback(msg); ok {
+				return m, cmd
+			}
+		}
+
+		// The sliders of the recommendations view take the arrow keys
+		if m.currentView == viewRecommendations {
+			if cmd, ok := m.updateRecommendations(msg); ok {
+				return m, cmd
+			}
+		}
+
 		switch msg.String() {
 		case "q", "esc":
-			// Return to the menu and blur the table
-			switch m.currentView {
-			case viewArtists:
-				m.artistTable.Blur()
-			case viewSongs:
-				m.songTable.Blur()
+			// Clear the filter of the current list first
+			if list, t := m.currentList(); list != nil && list.query != "" && msg.String() == "esc" {
+				list.clearFilter(t)
+				return m, nil
 			}
-			if m.currentView != viewMenu {
-				m.currentView = viewMenu
+
+			// Go back to the previous view, quitting from the menu
+			if m.pop() {
 				return m, nil
 			}
 			return m, tea.Quit
 
+		case "H":
+			// Go back to the menu
+			if m.currentView != viewEnterClientID {
+				m.home()
+				return m, nil
+			}
+
 		case "a", "A":
+			// Open the artist of the track being shown
+			if m.currentView == viewTrackDetail {
+				return m, m.openTrackArtist()
+			}
+
 			// Only switch to the Artists view if in the main menu
 			if m.currentView == viewMenu {
 				m.artistTable.Focus()
-				return m, func() tea.Msg {
-					response, err := fetchArtistsPage("https://api.spotify.com/v1/me/top/artists?time_range=medium_term")
-					if err != nil {
-						return errMsg{err}
-					}
-					return switchToArtistsMsg{response}
-				}
+				return m, m.loadView(viewArtists)
 			}
 
 		case "s", "S":
 			// Only switch to the Songs view if in the main menu
 			if m.currentView == viewMenu {
 				m.songTable.Focus()
-				return m, func() tea.Msg {
-					response, err := fetchSongsPage("https://api.spotify.com/v1/me/top/tracks?time_range=medium_term")
-					if err != nil {
-						return errMsg{err}
-					}
-					return switchToSongsMsg{response}
+				return m, m.loadView(viewSongs)
+			}
+
+		case "r", "R":
+			// Only switch to the Recently Played view if in the main menu
+			if m.currentView == viewMenu {
+				return m, m.loadView(viewRecent)
+			}
+
+		case "p", "P":
+			// Only switch to the Playlists view if in the main menu
+			if m.currentView == viewMenu {
+				return m, m.loadView(viewPlaylists)
+			}
+
+		case "f", "F":
+			// Only switch to the Followed Artists view if in the main menu
+			if m.currentView == viewMenu {
+				return m, m.loadView(viewFollowed)
+			}
+
+		case "g", "G":
+			// Only switch to the Genres view if in the main menu
+			if m.currentView == viewMenu {
+				return m, m.openGenres()
+			}
+
+		case "/":
+			// Open the catalog search from the menu
+			if m.currentView == viewMenu {
+				return m, m.openSearch()
+			}
+
+			// Filter the current list
+			if list, _ := m.currentList(); list != nil {
+				return m, list.openFilter()
+			}
+
+		case "o", "O":
+			// Sort the current list by the next column, or reverse the sort
+			if list, t := m.currentList(); list != nil {
+				if msg.String() == "o" {
+					list.cycleSort(t)
+				} else {
+					list.reverseSort(t)
 				}
+				return m, nil
 			}
 
-		case "1":
-			// fetch short term artists or songs
+		case "1", "2", "3":
+			// fetch short, medium or long term artists or songs
+			if m.currentView == viewArtists || m.currentView == viewSongs {
+				m.timeRange = timeRanges[msg.String()[0]-'1']
+				m.focusView(m.currentView)
+				return m, m.loadView(m.currentView)
+			}
+
+			// Break down the genres of another time range
+			if m.currentView == viewGenres {
+				m.timeRange = timeRanges[msg.String()[0]-'1']
+				m.setGenreRows()
+				m.genres.table.SetCursor(0)
+				return m, m.loadView(viewGenres)
+			}
+
+		case "l", "L":
+			// Switch to the Liked Songs view from the main menu
+			if m.currentView == viewMenu {
+				return m, m.loadView(viewLiked)
+			}
+
+			// Toggle between paging and loading every top item
+			if msg.String() == "L" && (m.currentView == viewArtists || m.currentView == viewSongs) {
+				m.loadAll = !m.loadAll
+				return m, m.loadView(m.currentView)
+			}
+
+		case "c":
+			// Compare the time ranges of the current list side by side
 			switch m.currentView {
 			case viewArtists:
-				m.artistTable.Focus()
-				return m, func() tea.Msg {
-					response, err := fetchArtistsPage("https://api.spotify.com/v1/me/top/artists?time_range=short_term")
-					if err != nil {
-						return errMsg{err}
-					}
-					return switchToArtistsMsg{response}
-				}
+				m.compareView, m.compareOffset = viewArtists, 0
+				m.pushView(viewCompare)
+				return m, m.fetchRangeArtistsCmds()
 			case viewSongs:
-				m.songTable.Focus()
-				return m, func() tea.Msg {
-					response, err := fetchSongsPage("https://api.spotify.com/v1/me/top/tracks?time_range=short_term")
-					if err != nil {
-						return errMsg{err}
-					}
-					return switchToSongsMsg{response}
-				}
+				m.compareView, m.compareOffset = viewSongs, 0
+				m.pushView(viewCompare)
+				return m, m.fetchRangeSongsCmds()
 			}
-		case "2":
-			// medium
-			switch m.currentView {
-			case viewSongs:
-				m.songTable.Focus()
-				return m, func() tea.Msg {
-					response, err := fetchSongsPage("https://api.spotify.com/v1/me/top/tracks?time_range=medium_term")
-					if err != nil {
-						return errMsg{err}
-					}
-					return switchToSongsMsg{response}
-				}
 
-			case viewArtists:
-				m.artistTable.Focus()
-				return m, func() tea.Msg {
-					response, err := fetchArtistsPage("https://api.spotify.com/v1/me/top/artists?time_range=medium_term")
-					if err != nil {
-						return errMsg{err}
-					}
-					return switchToArtistsMsg{response}
-				}
+		case "ctrl+s":
+			// Save the top songs, as shown, to a playlist
+			if m.currentView == viewSongs {
+				return m, m.saveShownSongs()
 			}
-		case "3":
-			// long
-			switch m.currentView {
-			case viewSongs:
-				m.songTable.Focus()
-				return m, func() tea.Msg {
-					response, err := fetchSongsPage("https://api.spotify.com/v1/me/top/tracks?time_range=long_term")
-					if err != nil {
-						return errMsg{err}
-					}
-					return switchToSongsMsg{response}
+
+		case "*":
+			// Like or unlike the selected song, or follow or unfollow the
+			// selected artist. The key is kept away from H, which goes home
+			return m, m.toggleSelected()
+
+		case "m":
+			// Mark or unmark the selected top artist or song as a seed of
+			// the recommendations
+			return m, m.toggleSeed()
+
+		case "M":
+			// Show recommendations for the seeds
+			if m.currentView == viewArtists || m.currentView == viewSongs {
+				return m, m.openRecommendations()
+			}
+
+		case "i":
+			// Add the selected track to a playlist
+			return m, m.pickPlaylist()
+
+		case "x":
+			// Remove the selected track from the open playlist
+			return m, m.editSelectedTrack(editRemove, 0)
+
+		case "J", "K":
+			// Move the selected track of the open playlist down or up
+			step := 1
+			if msg.String() == "K" {
+				step = -1
+			}
+			return m, m.editSelectedTrack(editMove, step)
+
+		case "z":
+			// Undo the last playlist edit
+			return m, m.undoPlaylistEdit()
+
+		case "t":
+			if m.currentView == viewCompare {
+				// Retry a failed range rather than moving on from it
+				if m.compareErr() != nil {
+					return m, m.retryCompare()
 				}
-			case viewArtists:
-				m.artistTable.Focus()
-				return m, func() tea.Msg {
-					response, err := fetchArtistsPage("https://api.spotify.com/v1/me/top/artists?time_range=long_term")
-					if err != nil {
-						return errMsg{err}
-					}
-					return switchToArtistsMsg{response}
+				m.compareRanges = (m.compareRanges + 1) % len(compareRangeSets)
+				return m, nil
+			}
+
+		case "up", "k":
+			if m.currentView == viewCompare && m.compareOffset > 0 {
+				m.compareOffset--
+				return m, nil
+			}
+
+		case "down", "j":
+			if m.currentView == viewCompare {
+				if m.compareOffset < m.compareLength()-1 {
+					m.compareOffset++
 				}
+				return m, nil
 			}
 
 		case "right": // Handle next page for Artists or Songs
 			if m.currentView == viewArtists && m.artists.Next != "" {
-				return m, func() tea.Msg {
-					response, err := fetchArtistsPage(m.artists.Next)
-					if err != nil {
-						return errMsg{err}
-					}
-					return switchToArtistsMsg{response}
-				}
+				return m, fetchArtistsCmd(m.artists.Next, m.currentView)
 			} else if m.currentView == viewSongs && m.songs.Next != "" {
-				return m, func() tea.Msg {
-					response, err := fetchSongsPage(m.songs.Next)
-					if err != nil {
-						return errMsg{err}
-					}
-					return switchToSongsMsg{response}
-				}
+				return m, fetchSongsCmd(m.songs.Next, m.currentView)
+			} else if _, ok := m.lists[m.currentView]; ok {
+				return m, m.turnPage(true)
 			}
 
 		case "left": // Handle previous page for Artists or Songs
 			if m.currentView == viewArtists && m.artists.Prev != "" {
-				return m, func() tea.Msg {
-					response, err := fetchArtistsPage(m.artists.Prev)
-					if err != nil {
-						return errMsg{err}
-					}
-					return switchToArtistsMsg{response}
-				}
+				return m, fetchArtistsCmd(m.artists.Prev, m.currentView)
 			} else if m.currentView == viewSongs && m.songs.Prev != "" {
-				return m, func() tea.Msg {
-					response, err := fetchSongsPage(m.songs.Prev)
-					if err != nil {
-						return errMsg{err}
-					}
-					return switchToSongsMsg{response}
+				return m, fetchSongsCmd(m.songs.Prev, m.currentView)
+			} else if _, ok := m.lists[m.currentView]; ok {
+				return m, m.turnPage(false)
+			}
+
+		case "b", "B":
+			// Switch to the Saved Albums view from the main menu
+			if m.currentView == viewMenu {
+				return m, m.loadView(viewSavedAlbums)
+			}
+
+			// Open the album of the track being shown, or go back to it if the
+			// track was opened from there
+			if m.currentView == viewTrackDetail && msg.String() == "b" {
+				return m, m.openTrackAlbum()
+			}
+
+		case "tab", "shift+tab":
+			// Cycle the sections of the artist detail view
+			if m.currentView == viewArtistDetail {
+				d := m.top().artist
+				step := 1
+				if msg.String() == "shift+tab" {
+					step = len(artistSectionTitles) - 1
 				}
+				d.section = (d.section + step) % len(artistSectionTitles)
+				return m, nil
 			}
 
 		case "enter":
+			// Open the selected track or artist
+			if m.openTrackDetail() {
+				return m, nil
+			}
+			if cmd := m.openAlbum(); cmd != nil {
+				return m, cmd
+			}
+			if m.currentView == viewPlaylists {
+				return m, m.openSelectedPlaylist()
+			}
+			if m.currentView == viewDevices {
+				return m, m.transferToSelected()
+			}
+			if m.currentView == viewPlaylistPicker {
+				return m, m.addToPickedPlaylist()
+			}
+			if m.openGenreArtists() {
+				return m, nil
+			}
+			if m.currentView == viewArtists || m.currentView == viewArtistDetail || m.currentView == viewFollowed || m.currentView == viewGenreArtists {
+				return m, m.openArtistDetail()
+			}
+
 			// Handle entering the Client ID
 			if m.currentView == viewEnterClientID {
 				m.clientID = m.textInput.Value()
@@ -204,30 +353,16 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.me = me
 
-				// Initialize artist table
-				m.artistTable = table.New(
-					table.WithColumns([]table.Column{
-						{Title: "Name", Width: 40},
-						{Title: "Genres", Width: 50},
-						{Title: "Popularity", Width: 10},
-					}),
-					table.WithFocused(false),
-				)
-
-				// Initialize song table
-				m.songTable = table.New(
-					table.WithColumns([]table.Column{
-						{Title: "Name", Width: 40},
-						{Title: "Artist", Width: 20},
-						{Title: "Album", Width: 30},
-						{Title: "Popularity", Width: 10},
-					}),
-					table.WithFocused(false),
-				)
-
-				// Switch to the menu view after successful login
+				m.artistTable = newArtistTable()
+				m.songTable = newSongTable()
+
+				// Switch to the menu, or the start view, after successful login
+				m.stack = []navEntry{{view: viewMenu, title: viewTitles[viewMenu]}}
 				m.currentView = viewMenu
-				return m, nil
+				if m.startView != viewMenu {
+					m.pushView(m.startView)
+				}
+				return m, tea.Batch(m.loadView(m.currentView), m.startBackground())
 			}
 		}
 
@@ -237,41 +372,202 @@ func (m appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.artistTable, cmd = m.artistTable.Update(msg)
 		case viewSongs:
 			m.songTable, cmd = m.songTable.Update(msg)
+		default:
+			if l, ok := m.lists[m.currentView]; ok {
+				l.table, cmd = l.table.Update(msg)
+			}
+		case viewArtistDetail:
+			d := m.top().artist
+			d.tables[d.section], cmd = d.tables[d.section].Update(msg)
+		case viewAlbum:
+			d := m.top().album
+			d.table, cmd = d.table.Update(msg)
+		case viewGenres:
+			m.genres.table, cmd = m.genres.table.Update(msg)
+		case viewGenreArtists:
+			m.genres.artistTable, cmd = m.genres.artistTable.Update(msg)
 		}
 
 	case tea.WindowSizeMsg:
 		m.windowSize = msg
 
 		// Recalculate column widths
-		m.artistColWidths = calculateColumnWidths(msg.Width, []float64{0.4, 0.4, 0.2})
-		m.songColWidths = calculateColumnWidths(msg.Width, []float64{0.4, 0.2, 0.2, 0.2})
+		m.artistColWidths = calculateColumnWidths(msg.Width, artistColRatios)
+		m.songColWidths = calculateColumnWidths(msg.Width, songColRatios)
+		for view, l := range m.lists {
+			l.widths = calculateColumnWidths(msg.Width, listSources[view].ratios)
+		}
+		m.genres.colWidths = calculateColumnWidths(msg.Width, genreColRatios)
+		m.genres.artistWidths = calculateColumnWidths(msg.Width, genreArtistColRatios)
+		m.setGenreRows()
 
 	case switchToArtistsMsg:
+		if m.currentView != viewArtists && m.currentView != msg.from {
+			return m, nil
+		}
 		m.artists = msg.response
-		rows := []table.Row{}
-		for _, artist := range m.artists.Artists {
-			rows = append(rows, table.Row{artist.Name, artist.Genres, fmt.Sprintf("%d", artist.Popularity)})
+		m.setArtistRows()
+		m.artistTable.SetCursor(0)
+		if m.currentView != viewArtists {
+			m.open(navEntry{view: viewArtists})
 		}
-		m.artistTable.SetRows(rows)
-		m.currentView = viewArtists
+		return m, tea.Batch(m.fetchRangeArtistsCmds(), fetchFollowedArtistsCmd(m.artists.Artists))
 
 	case switchToSongsMsg:
+		if m.currentView != viewSongs && m.currentView != msg.from {
+			return m, nil
+		}
 		m.songs = msg.response
-		rows := []table.Row{}
-		for _, song := range m.songs.Songs {
-			rows = append(rows, table.Row{song.Name, song.Artist, song.Album, fmt.Sprintf("%d", song.Popularity)})
+		m.setSongRows()
+		m.songTable.SetCursor(0)
+		if m.currentView != viewSongs {
+			m.open(navEntry{view: viewSongs})
+		}
+		return m, tea.Batch(m.fetchRangeSongsCmds(), fetchSavedTracksCmd(m.songs.Songs))
+
+	case switchToPageMsg:
+		m.showPage(msg)
+
+	case playbackMsg:
+		m.playbackErr = msg.err
+		if msg.err == nil {
+			m.playback = msg.state
+		} else if errors.Is(msg.err, errVolumeUnknown) {
+			// The state was fetched fresh; only the volume change was refused
+			m.playback = msg.state
+			return m, m.showToast(msg.err.Error())
+		}
+
+	case transferMsg:
+		m.playbackErr = msg.err
+		if msg.err == nil {
+			m.playback = msg.state
+			m.replacePage(viewDevices, msg.devices)
+		}
+
+	case containsMsg:
+		m.storeContains(msg)
+
+	case libraryToggledMsg:
+		return m, m.libraryToggled(msg)
+
+	case recommendationsDebounceMsg:
+		return m, m.refetchRecommendations(msg)
+
+	case recommendationsMsg:
+		m.showRecommendations(msg)
+
+	case playlistEditedMsg:
+		return m, m.playlistEdited(msg)
+
+	case ownedPlaylistsMsg:
+		if m.prompt.active() {
+			m.prompt.owned = msg.playlists
+		}
+
+	case playlistSavedMsg:
+		return m, m.playlistSaved(msg)
+
+	case queuedMsg:
+		return m, m.queued(msg)
+
+	case toastExpiredMsg:
+		if msg.seq == m.toastSeq {
+			m.toast = ""
+		}
+		return m, nil
+
+	case pollTickMsg:
+		return m, pollCurrentlyPlayingCmd()
+
+	case currentlyPlayingMsg:
+		return m, m.updateCurrentlyPlaying(msg)
+
+	case progressTickMsg:
+		return m, tickProgressCmd()
+
+	case libraryCountsMsg:
+		m.libraryCounts = msg.counts
+
+	case switchToArtistDetailMsg:
+		m.pushArtistDetail(msg.detail)
+
+	case switchToAlbumMsg:
+		m.pushAlbum(msg.detail)
+
+	case rangeArtistsMsg:
+		m.storeRangeArtists(msg)
+		m.setArtistRows()
+		m.setGenreRows()
+
+	case rangeSongsMsg:
+		m.storeRangeSongs(msg)
+		m.setSongRows()
+
+	case searchDebounceMsg:
+		return m, m.runSearch(msg)
+
+	case searchResultsMsg:
+		// Drop results of queries that have since been edited
+		if msg.seq == m.search.seq {
+			m.search.err = msg.err
+			if msg.err == nil {
+				m.search.setResults(msg.results)
+			}
 		}
-		m.songTable.SetRows(rows)
-		m.currentView = viewSongs
 
 	case errMsg:
 		m.err = msg.err
 	}
 
 	// Update the text input model
-	if m.currentView == viewEnterClientID {
+	switch m.currentView {
+	case viewEnterClientID:
 		m.textInput, cmd = m.textInput.Update(msg)
+	case viewSearch:
+		m.search.input, cmd = m.search.input.Update(msg)
 	}
 
 	return m, cmd
 }
+
+// setArtistRows fills the artist table from the current page of artists.
+func (m *appModel) setArtistRows() {
+	baseline := m.baselineArtistRanks()
+	rows := []table.Row{}
+	var changes []string
+	for _, artist := range m.artists.Artists {
+		changes = append(changes, rankChangeKey(artist.ID, artist.Rank, baseline))
+		rows = append(rows, table.Row{
+			fmt.Sprintf("%d", artist.Rank),
+			rankChange(artist.ID, artist.Rank, baseline, m.absentLabel()),
+			checkMark(m.followedArtists[artist.ID], "✓"),
+			artist.Name,
+			artist.Genres,
+			fmt.Sprintf("%d", artist.Popularity),
+		})
+	}
+	m.artistList.keys = map[int][]string{1: changes}
+	m.artistList.set(&m.artistTable, rows)
+}
+
+// setSongRows fills the song table from the current page of songs.
+func (m *appModel) setSongRows() {
+	baseline := m.baselineSongRanks()
+	rows := []table.Row{}
+	var changes []string
+	for _, song := range m.songs.Songs {
+		changes = append(changes, rankChangeKey(song.ID, song.Rank, baseline))
+		rows = append(rows, table.Row{
+			fmt.Sprintf("%d", song.Rank),
+			rankChange(song.ID, song.Rank, baseline, m.absentLabel()),
+			checkMark(m.likedSongs[song.ID], "♥"),
+			song.Name,
+			song.Artist,
+			song.Album,
+			fmt.Sprintf("%d", song.Popularity),
+		})
+	}
+	m.songList.keys = map[int][]string{1: changes}
+	m.songList.set(&m.songTable, rows)
+}
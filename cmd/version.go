@@ -0,0 +1,19 @@
+package cmd
+
+import "runtime/debug"
+
+// Version is the version of the tool, set at build time with
+// -ldflags "-X github.com/bytegrunt/go-spotify-me/cmd.Version=v1.2.3".
+var Version = "dev"
+
+// toolVersion returns Version, falling back to the module version recorded by
+// go install when it was not set at build time.
+func toolVersion() string {
+	if Version != "dev" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return Version
+}
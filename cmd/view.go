@@ -8,28 +8,95 @@ import (
 	"github.com/charmbracelet/bubbles/table"
 )
 
-var footer = theme.HelpStyle.Render("[↑/↓] Navigate  [←] Prev Page  [→] Next Page  [1] Short  [2] Medium  [3] Long  [q] Back")
+var footer = theme.HelpStyle.Render("[↑/↓] Navigate  [←] Prev Page  [→] Next Page  [1] Short  [2] Medium  [3] Long  [L] Load All  [c] Compare  [/] Filter  [o/O] Sort  [*] Like/Follow  [m] Seed  [M] Recommend  [ctrl+s] Save as Playlist  [enter] Details  [q] Back  [H] Home")
+
+// tableChrome is the number of lines around the rows of a table view: the
+// breadcrumbs, the title, the container border, margin and padding, the
+// header, the footer and the status line.
+const tableChrome = 16
 
 func (m appModel) View() string {
 	if m.err != nil {
 		return fmt.Sprintf("Error: %v\nPress q to quit.", m.err)
 	}
 
+	if m.currentView == viewEnterClientID {
+		return m.renderView()
+	}
+	return m.renderBreadcrumbs() + m.renderView() + "\n" + m.renderStatusLine()
+}
+
+// renderView renders the current view, below the breadcrumbs.
+func (m appModel) renderView() string {
 	switch m.currentView {
 	case viewMenu:
 		return m.renderMenu()
 	case viewArtists:
-		return m.renderTable(m.artistTable, m.artistColWidths) + "\n" + footer
+		return m.renderTitle("Top Artists") + m.renderListWithin(m.artistList, m.artistTable, m.artistColWidths, m.isArtistSeedRow) + m.renderDropped(m.droppedArtists()) + "\n" + footer
 	case viewSongs:
-		return m.renderTable(m.songTable, m.songColWidths) + "\n" + footer
+		return m.renderTitle("Top Songs") + m.renderListWithin(m.songList, m.songTable, m.songColWidths, m.isSongSeedRow) + m.renderDropped(m.droppedSongs()) + "\n" + footer
 	case viewEnterClientID:
 		return m.renderEnterClientID()
+	case viewCompare:
+		return m.renderCompare()
+	case viewArtistDetail:
+		return m.renderArtistDetail()
+	case viewTrackDetail:
+		return m.renderTrackDetail()
+	case viewAlbum:
+		return m.renderAlbum()
+	case viewSearch:
+		return m.renderSearch()
+	case viewNowPlaying:
+		return m.renderNowPlaying()
+	case viewDevices:
+		return m.renderDevices()
+	case viewRecommendations:
+		return m.renderRecommendations()
+	case viewGenres:
+		return m.renderGenres()
+	case viewGenreArtists:
+		return m.renderGenreArtists()
+	case viewRecent, viewLiked, viewSavedAlbums, viewFollowed, viewPlaylists, viewPlaylistTracks, viewQueue, viewPlaylistPicker:
+		return m.renderPagedList()
 	default:
 		return "Unknown view"
 	}
 }
 
-func (m appModel) renderTable(t table.Model, colWidths []int) string {
+func (m appModel) renderTitle(title string) string {
+	title += " · " + timeRangeLabel(m.timeRange)
+	title += " (Δ vs " + timeRangeLabel(baselineRange(m.timeRange)) + ")"
+	return theme.TitleStyle.Render(title) + "\n"
+}
+
+// renderTableWithin renders a table that shares the window with chrome lines
+// of other content.
+func (m appModel) renderTableWithin(t table.Model, colWidths []int, chrome int) string {
+	return m.renderTableStyled(t, colWidths, chrome, nil, nil)
+}
+
+// renderList renders a filterable list table below its filter bar, with the
+// characters matched by the filter highlighted.
+func (m appModel) renderList(list tableRows, t table.Model, colWidths []int) string {
+	return m.renderListWithin(list, t, colWidths, nil)
+}
+
+// renderListWithin renders a filterable list table with the rows where
+// highlight, if not nil, reports true highlighted.
+func (m appModel) renderListWithin(list tableRows, t table.Model, colWidths []int, highlight func(row int) bool) string {
+	bar := list.renderFilter()
+	chrome := tableChrome
+	if bar != "" {
+		chrome++
+	}
+	return bar + m.renderTableStyled(t, colWidths, chrome, highlight, list.match)
+}
+
+// renderTableStyled renders a table whose rows are highlighted where
+// highlight, if not nil, reports true, and whose cells have the characters
+// returned by matches, if not nil, highlighted.
+func (m appModel) renderTableStyled(t table.Model, colWidths []int, chrome int, highlight func(row int) bool, matches func(row int) [][]int) string {
 	var rows []string
 
 	// Header
@@ -39,19 +106,47 @@ func (m appModel) renderTable(t table.Model, colWidths []int) string {
 	}
 	header := theme.RenderRow(headers, colWidths, theme.HeaderStyle)
 
-	// Body
-	for i, row := range t.Rows() {
+	// Body, scrolled to keep the cursor in view
+	start, end := m.visibleRows(len(t.Rows()), t.Cursor(), chrome)
+	for i, row := range t.Rows()[start:end] {
 		style := theme.RowStyle
-		if i == t.Cursor() {
+		if start+i == t.Cursor() {
 			style = theme.SelectedRowStyle
+		} else if highlight != nil && highlight(start+i) {
+			style = theme.HighlightRowStyle
+		}
+		var cellMatches [][]int
+		if matches != nil {
+			cellMatches = matches(start + i)
 		}
-		rows = append(rows, theme.RenderRow(row, colWidths, style))
+		rows = append(rows, theme.RenderRow(row, colWidths, style, cellMatches...))
 	}
 
 	body := header + "\n" + strings.Join(rows, "\n")
 	return theme.TableContainerStyle.Render(body)
 }
 
+// visibleRows returns the range of rows that fit in the window, centred on the
+// cursor where possible.
+func (m appModel) visibleRows(total, cursor, chrome int) (int, int) {
+	height := m.windowSize.Height - chrome
+	if m.windowSize.Height == 0 || total <= height {
+		return 0, total
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	start := cursor - height/2
+	if start < 0 {
+		start = 0
+	}
+	if start+height > total {
+		start = total - height
+	}
+	return start, start + height
+}
+
 func (m appModel) renderEnterClientID() string {
 	return fmt.Sprintf(
 		"Enter your Spotify Client ID:\n\n%s\n\nPress Enter to confirm, or Esc to quit.",
@@ -67,6 +162,11 @@ func (m appModel) renderMenu() string {
 		{"Country", m.me.Country},
 		{"Profile URL", m.me.ProfileURL},
 	}
+	for _, view := range []viewType{viewLiked, viewSavedAlbums, viewFollowed} {
+		if count, ok := m.libraryCounts[view]; ok {
+			rows = append(rows, []string{viewTitles[view], formatCount(count)})
+		}
+	}
 
 	if m.windowSize.Width < 20 {
 		m.windowSize.Width = 20
@@ -81,5 +181,5 @@ func (m appModel) renderMenu() string {
 	}
 
 	table := header + "\n" + strings.Join(renderedRows, "\n")
-	return theme.TableContainerStyle.Render(table) + "\n" + theme.HelpStyle.Render("[A] Top Artists  [S] Top Songs  [Q] Quit")
+	return theme.TableContainerStyle.Render(table) + "\n" + theme.HelpStyle.Render("[A] Top Artists  [S] Top Songs  [R] Recently Played  [L] Liked Songs  [B] Saved Albums  [F] Followed Artists  [P] Playlists  [N] Now Playing  [G] Genres  [/] Search  [Q] Quit")
 }
@@ -88,18 +88,20 @@ func ExchangeCodeForToken(authConfig AuthConfig, code, codeVerifier string) {
 	accessToken := tokenResponse["access_token"].(string)
 	refreshToken := tokenResponse["refresh_token"].(string)
 	expiresIn := int(tokenResponse["expires_in"].(float64)) // Convert to int
+	scope, _ := tokenResponse["scope"].(string)
 
 	// Calculate expiration time
 	expirationTime := time.Now().Add(time.Duration(expiresIn) * time.Second)
 
 	// Save the access token, refresh token, and expiration time to a hidden file
-	SaveAccessTokenToFile(accessToken, refreshToken, expirationTime)
+	SaveAccessTokenToFile(accessToken, refreshToken, scope, expirationTime)
 
 	fmt.Println("Refresh Token stored successfully.")
 }
 
-// Save the access token, refresh token, and expiration time to a hidden file
-func SaveAccessTokenToFile(accessToken, refreshToken string, expirationTime time.Time) {
+// Save the access token, refresh token, granted scopes and expiration time to
+// a hidden file
+func SaveAccessTokenToFile(accessToken, refreshToken, scope string, expirationTime time.Time) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		logger.Fatal("Failed to get user home directory", zap.Error(err))
@@ -130,9 +132,9 @@ func SaveAccessTokenToFile(accessToken, refreshToken string, expirationTime time
 	if err != nil {
 		logger.Error("Failed to store refresh token in keyring", zap.Error(err))
 		logger.Info("Falling back to saving the refresh token in the hidden file.")
-		data = fmt.Sprintf("access_token=%s\nrefresh_token=%s\nexpires_at=%s\n", accessToken, refreshToken, expirationTime.Format(time.RFC3339))
+		data = fmt.Sprintf("access_token=%s\nrefresh_token=%s\nexpires_at=%s\nscope=%s\n", accessToken, refreshToken, expirationTime.Format(time.RFC3339), scope)
 	} else {
-		data = fmt.Sprintf("access_token=%s\nexpires_at=%s\n", accessToken, expirationTime.Format(time.RFC3339))
+		data = fmt.Sprintf("access_token=%s\nexpires_at=%s\nscope=%s\n", accessToken, expirationTime.Format(time.RFC3339), scope)
 	}
 
 	_, err = file.WriteString(data)
@@ -178,9 +180,10 @@ func RefreshAccessToken(authConfig AuthConfig, refreshToken string) error {
 	}
 
 	accessToken := tokenResponse["access_token"].(string)
+	scope, _ := tokenResponse["scope"].(string)
 
 	// Save the new access token to a hidden file
-	SaveAccessTokenToFile(accessToken, refreshToken, time.Now().Add(3600*time.Second)) // Assuming 1 hour expiration
+	SaveAccessTokenToFile(accessToken, refreshToken, scope, time.Now().Add(3600*time.Second)) // Assuming 1 hour expiration
 
 	logging.DebugLog("Access Token refreshed successfully.")
 	return nil
@@ -243,3 +246,24 @@ func GetValidAccessToken() (string, bool) {
 
 	return accessToken, true
 }
+
+// GrantedScopes returns the scopes the saved access token was granted, or nil
+// if none were recorded, as for tokens saved by older versions.
+func GrantedScopes() []string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(homeDir, ".go-spotify-me-cli"))
+	if err != nil {
+		return nil
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "scope=") {
+			return strings.Fields(strings.TrimPrefix(line, "scope="))
+		}
+	}
+	return nil
+}
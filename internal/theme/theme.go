@@ -33,6 +33,11 @@ var RowStyle = lipgloss.NewStyle().
 	Background(colorBackground).
 	Padding(0, 1)
 
+// Style for highlighted rows, such as tracks already in the user's library
+var HighlightRowStyle = RowStyle.
+	Foreground(colorPrimary).
+	Bold(true)
+
 // Style for selected row
 var SelectedRowStyle = lipgloss.NewStyle().
 	Foreground(colorSelectedFG).
@@ -47,17 +52,92 @@ var TableContainerStyle = lipgloss.NewStyle().
 	Margin(1, 2).
 	Padding(1, 2)
 
+// Style for the title above a table
+var TitleStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(colorPrimary).
+	MarginLeft(2)
+
+// Style for items highlighted within a list, such as those shared by every
+// compared time range
+var HighlightStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(colorPrimary)
+
+// Style for secondary text, such as inactive tabs
+var MutedStyle = lipgloss.NewStyle().
+	Foreground(colorMuted)
+
+// Style to wrap one pane of a split view
+var PaneStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(colorMuted).
+	Padding(0, 1)
+
 // Style for the help/footer text
 var HelpStyle = lipgloss.NewStyle().
 	Foreground(colorMuted).
 	MarginTop(1)
 
-func RenderRow(cells []string, widths []int, style lipgloss.Style) string {
+// Style for the characters of a cell matched by a filter, on top of the row's
+// own style
+var matchStyle = lipgloss.NewStyle().
+	Underline(true).
+	Foreground(colorPrimary)
+
+// RenderRow renders one row of a table. Matches, if given, holds the rune
+// positions of each cell to highlight.
+func RenderRow(cells []string, widths []int, style lipgloss.Style, matches ...[]int) string {
 	rendered := make([]string, len(cells))
 	for i, cell := range cells {
 		rendered[i] = TruncateOrPad(cell, widths[i])
 	}
-	return style.Render(strings.Join(rendered, " | "))
+	if len(matches) == 0 {
+		return style.Render(strings.Join(rendered, " | "))
+	}
+
+	// Styled segments cannot be nested, so the row is built from segments
+	// rendered one by one, with the row's padding added around them.
+	base := style.UnsetPadding()
+	highlight := matchStyle.Inherit(base)
+
+	var b strings.Builder
+	b.WriteString(base.Render(strings.Repeat(" ", style.GetPaddingLeft())))
+	for i, cell := range rendered {
+		if i > 0 {
+			b.WriteString(base.Render(" | "))
+		}
+
+		matched := make(map[int]bool)
+		if i < len(matches) {
+			for _, pos := range matches[i] {
+				matched[pos] = true
+			}
+		}
+
+		// Positions past a truncated cell's text are not shown
+		runes := []rune(cell)
+		visible := len(runes)
+		if len([]rune(cells[i])) > widths[i] && widths[i] > 3 {
+			visible = widths[i] - 3
+		}
+
+		start := 0
+		for j := 1; j <= len(runes); j++ {
+			if j < len(runes) && (matched[j] && j < visible) == (matched[start] && start < visible) {
+				continue
+			}
+			segment := string(runes[start:j])
+			if matched[start] && start < visible {
+				b.WriteString(highlight.Render(segment))
+			} else {
+				b.WriteString(base.Render(segment))
+			}
+			start = j
+		}
+	}
+	b.WriteString(base.Render(strings.Repeat(" ", style.GetPaddingRight())))
+	return b.String()
 }
 
 func TruncateOrPad(s string, width int) string {
@@ -3,9 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/bytegrunt/go-spotify-me/cmd"
-	tea "github.com/charmbracelet/bubbletea"
 	"go.uber.org/zap"
 )
 
@@ -28,25 +28,29 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Check for the --clear-config flag
-	for _, arg := range os.Args {
-		if arg == "--clear-config" {
-			if err := cmd.ClearConfig(); err != nil {
-				logger.Fatal("Failed to clear configuration", zap.Error(err))
-			}
-			fmt.Println("Configuration cleared successfully.")
-			return
+	// --clear-config is a global flag only in place of a subcommand, so that
+	// it is never acted on as an argument, such as a word being completed
+	if len(os.Args) > 1 && os.Args[1] == "--clear-config" {
+		if err := cmd.ClearConfig(); err != nil {
+			logger.Fatal("Failed to clear configuration", zap.Error(err))
 		}
+		fmt.Println("Configuration cleared successfully.")
+		return
 	}
 
-	clientID, err := cmd.GetClientID()
-	if err != nil {
-		logger.Fatal("Failed to retrieve client ID", zap.Error(err))
+	// Launch the TUI unless a subcommand was given
+	args := os.Args[1:]
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		args = append([]string{"tui"}, args...)
 	}
 
-	// Initialize the app model with the client ID
-	p := tea.NewProgram(cmd.InitialAppModel(clientID), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		logger.Fatal("Error starting TUI", zap.Error(err))
+	handled, err := cmd.Execute(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !handled {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n", args[0])
+		os.Exit(1)
 	}
 }